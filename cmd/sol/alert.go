@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// alertMetric is a daily value that -alert conditions can compare
+type alertMetric struct {
+	value func(DailyEntry) *float64
+	unit  func(ReportUnits) string
+}
+
+// alertMetrics are the names -alert knows, with thresholds in the forecast's units
+var alertMetrics = map[string]alertMetric{
+	"temp_max": {
+		value: func(d DailyEntry) *float64 { return d.TemperatureMax },
+		unit:  func(u ReportUnits) string { return u.Temperature },
+	},
+	"temp_min": {
+		value: func(d DailyEntry) *float64 { return d.TemperatureMin },
+		unit:  func(u ReportUnits) string { return u.Temperature },
+	},
+	"precip": {
+		value: func(d DailyEntry) *float64 { return d.PrecipitationSum },
+		unit:  func(u ReportUnits) string { return " " + u.Precipitation },
+	},
+	"precip_prob": {
+		value: func(d DailyEntry) *float64 { return d.PrecipitationProbabilityMax },
+		unit:  func(ReportUnits) string { return "%" },
+	},
+	"wind": {
+		value: func(d DailyEntry) *float64 { return d.WindSpeedMax },
+		unit:  func(u ReportUnits) string { return " " + u.WindSpeed },
+	},
+	"uv": {
+		value: func(d DailyEntry) *float64 { return d.UVIndexMax },
+		unit:  func(ReportUnits) string { return "" },
+	},
+}
+
+// alertMetricNames lists the metrics for error messages
+func alertMetricNames() []string {
+	names := make([]string, 0, len(alertMetrics))
+	for name := range alertMetrics {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// alertCondition is one comparison like temp_max>35
+type alertCondition struct {
+	Metric    string
+	Operator  string
+	Threshold float64
+}
+
+// alertExpr is a parsed -alert value: it matches when all the conditions of
+// any one group do, so && binds tighter than ||
+type alertExpr [][]alertCondition
+
+// parseAlert reads an -alert value like "temp_max>35 || precip_prob>80 && wind>=40"
+func parseAlert(value string) (alertExpr, error) {
+	var expr alertExpr
+	for _, alternative := range strings.Split(value, "||") {
+		var group []alertCondition
+		for _, part := range strings.Split(alternative, "&&") {
+			condition, err := parseAlertCondition(part)
+			if err != nil {
+				return nil, fmt.Errorf("alert %q: %w", value, err)
+			}
+			group = append(group, condition)
+		}
+		expr = append(expr, group)
+	}
+	return expr, nil
+}
+
+// parseAlertCondition reads one comparison of a metric with a number
+func parseAlertCondition(text string) (alertCondition, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return alertCondition{}, fmt.Errorf("empty condition")
+	}
+	i := strings.IndexAny(text, "<>=!")
+	if i < 0 {
+		return alertCondition{}, fmt.Errorf("condition %q has no comparison, use one of <, <=, > or >=", text)
+	}
+
+	condition := alertCondition{Metric: strings.TrimSpace(text[:i])}
+	if _, ok := alertMetrics[condition.Metric]; !ok {
+		return alertCondition{}, fmt.Errorf("unknown metric %q, use one of %s", condition.Metric, strings.Join(alertMetricNames(), ", "))
+	}
+	rest := text[i:]
+	switch {
+	case strings.HasPrefix(rest, "<="), strings.HasPrefix(rest, ">="):
+		condition.Operator = rest[:2]
+	case strings.HasPrefix(rest, "<"), strings.HasPrefix(rest, ">"):
+		condition.Operator = rest[:1]
+	default:
+		return alertCondition{}, fmt.Errorf("condition %q has an unknown comparison, use one of <, <=, > or >=", text)
+	}
+
+	number := strings.TrimSpace(rest[len(condition.Operator):])
+	threshold, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return alertCondition{}, fmt.Errorf("condition %q needs a number after %s", text, condition.Operator)
+	}
+	condition.Threshold = threshold
+	return condition, nil
+}
+
+// matches compares the day's value with the threshold; a missing value never matches
+func (c alertCondition) matches(day DailyEntry) bool {
+	value := alertMetrics[c.Metric].value(day)
+	if value == nil {
+		return false
+	}
+	switch c.Operator {
+	case "<":
+		return *value < c.Threshold
+	case "<=":
+		return *value <= c.Threshold
+	case ">":
+		return *value > c.Threshold
+	default:
+		return *value >= c.Threshold
+	}
+}
+
+// evaluate returns the conditions of the first group that all match day, or nil
+func (e alertExpr) evaluate(day DailyEntry) []alertCondition {
+	for _, group := range e {
+		if !slices.ContainsFunc(group, func(c alertCondition) bool { return !c.matches(day) }) {
+			return group
+		}
+	}
+	return nil
+}
+
+// alertSentence explains a match like
+// "Alert for Seville on 2026-07-14: temp_max 38.2°C > 35, uv 9.1 >= 8"
+func alertSentence(place, date string, day DailyEntry, matched []alertCondition, units ReportUnits) string {
+	reasons := make([]string, len(matched))
+	for i, c := range matched {
+		metric := alertMetrics[c.Metric]
+		reasons[i] = fmt.Sprintf("%s %.1f%s %s %g", c.Metric, *metric.value(day), metric.unit(units), c.Operator, c.Threshold)
+	}
+	return fmt.Sprintf("Alert for %s on %s: %s", place, date, strings.Join(reasons, ", "))
+}
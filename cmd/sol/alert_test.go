@@ -0,0 +1,165 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+var (
+	metricUnits   = ReportUnits{Temperature: "°C", Precipitation: "mm", WindSpeed: "km/h"}
+	imperialUnits = ReportUnits{Temperature: "°F", Precipitation: "inch", WindSpeed: "mph"}
+)
+
+// alertDay is a hot, dry day without a UV index
+func alertDay() DailyEntry {
+	return DailyEntry{
+		Date:                        "2026-07-14",
+		TemperatureMax:              ptr(35),
+		TemperatureMin:              ptr(21),
+		PrecipitationSum:            ptr(0),
+		PrecipitationProbabilityMax: ptr(10),
+		WindSpeedMax:                ptr(20),
+	}
+}
+
+// alertMatch parses value and returns the matching conditions for day as text
+func alertMatch(t *testing.T, value string, day DailyEntry) string {
+	t.Helper()
+	expr, err := parseAlert(value)
+	if err != nil {
+		t.Fatalf("parseAlert(%q): %v", value, err)
+	}
+	var matched []string
+	for _, c := range expr.evaluate(day) {
+		matched = append(matched, c.Metric+c.Operator)
+	}
+	return strings.Join(matched, " ")
+}
+
+func TestAlertPrecedence(t *testing.T) {
+	hot := alertDay()
+	stormy := alertDay()
+	stormy.TemperatureMax, stormy.PrecipitationProbabilityMax, stormy.WindSpeedMax = ptr(25), ptr(90), ptr(50)
+	wet := alertDay()
+	wet.TemperatureMax, wet.PrecipitationProbabilityMax = ptr(25), ptr(90)
+	tests := []struct {
+		name string
+		day  DailyEntry
+		want string
+	}{
+		{"first alternative", hot, "temp_max>="},
+		{"both conditions of the second", stormy, "precip_prob> wind>="},
+		{"one condition of the second", wet, ""},
+	}
+	// && binds tighter than || whichever way round they are written
+	for _, value := range []string{
+		"temp_max>=35 || precip_prob>80 && wind>=40",
+		"precip_prob>80 && wind>=40 || temp_max>=35",
+	} {
+		for _, tt := range tests {
+			t.Run(value+"/"+tt.name, func(t *testing.T) {
+				if got := alertMatch(t, value, tt.day); got != tt.want {
+					t.Errorf("matched %q, want %q", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestAlertComparisons(t *testing.T) {
+	tests := []struct {
+		value   string
+		matches bool
+	}{
+		{"temp_max<35", false},
+		{"temp_max<=35", true},
+		{"temp_max>35", false},
+		{"temp_max>=35", true},
+		{"temp_max<35.1", true},
+		{"temp_max>34.9", true},
+		{" temp_max >= 35 ", true},
+		{"precip<=0", true},
+		{"temp_min>20 && wind<20", false},
+		// A missing value never matches, whatever the comparison
+		{"uv<3", false},
+		{"uv>=0", false},
+		{"uv>=0 || temp_min<25", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := alertMatch(t, tt.value, alertDay()) != ""; got != tt.matches {
+				t.Errorf("matches = %v, want %v", got, tt.matches)
+			}
+		})
+	}
+}
+
+func TestAlertUnits(t *testing.T) {
+	// Thresholds are in the forecast's units and are not converted
+	metric := alertDay()
+	imperial := alertDay()
+	imperial.TemperatureMax, imperial.WindSpeedMax = ptr(95), ptr(12.4)
+	tests := []struct {
+		name     string
+		day      DailyEntry
+		units    ReportUnits
+		value    string
+		sentence string
+	}{
+		{"metric", metric, metricUnits, "temp_max>30 && wind>=20", "Alert for Seville on 2026-07-14: temp_max 35.0°C > 30, wind 20.0 km/h >= 20"},
+		{"imperial", imperial, imperialUnits, "temp_max>=95 && wind>12", "Alert for Seville on 2026-07-14: temp_max 95.0°F >= 95, wind 12.4 mph > 12"},
+		{"metric with an imperial threshold", metric, metricUnits, "temp_max>=95", ""},
+		{"imperial with a metric threshold", imperial, imperialUnits, "wind>=20", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parseAlert(tt.value)
+			if err != nil {
+				t.Fatalf("parseAlert(%q): %v", tt.value, err)
+			}
+			matched := expr.evaluate(tt.day)
+			if tt.sentence == "" {
+				if matched != nil {
+					t.Errorf("%q matched %v", tt.value, matched)
+				}
+				return
+			}
+			if got := alertSentence("Seville", tt.day.Date, tt.day, matched, tt.units); got != tt.sentence {
+				t.Errorf("alertSentence = %q, want %q", got, tt.sentence)
+			}
+		})
+	}
+}
+
+func TestParseAlertErrors(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "empty condition"},
+		{"   ", "empty condition"},
+		{"temp_max>", "needs a number"},
+		{">35", "unknown metric"},
+		{"foo>1", "unknown metric"},
+		{"temp_max>>3", "needs a number"},
+		{"temp_max>35 &&", "empty condition"},
+		{"&& temp_max>35", "empty condition"},
+		{"temp_max>35 ||", "empty condition"},
+		{"temp_max>35 && && wind>40", "empty condition"},
+		{"temp_max 35", "has no comparison"},
+		{"temp_max=35", "unknown comparison"},
+		{"temp_max!=35", "unknown comparison"},
+		{"temp_max>hot", "needs a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			expr, err := parseAlert(tt.value)
+			if err == nil {
+				t.Fatalf("parseAlert(%q) = %v, want an error", tt.value, expr)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseAlert(%q) = %q, want an error about %q", tt.value, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import "math"
+
+// sparkBlocks are the glyphs used by sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// asciiBlocks replace sparkBlocks on terminals without Unicode block characters
+var asciiBlocks = []rune("_.-~=+*#")
+
+// sparkline draws the values as width glyphs, scaled between the smallest and
+// largest value. When there are more values than width they are averaged in
+// buckets; a width of 0 draws one glyph per value. Missing values (NaN) are
+// drawn as a space.
+func sparkline(values []float64, width int, ascii bool) string {
+	blocks := sparkBlocks
+	if ascii {
+		blocks = asciiBlocks
+	}
+	if width > 0 && width < len(values) {
+		values = resample(values, width)
+	}
+
+	low, high, _ := seriesRange(values)
+	line := make([]rune, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+			line[i] = ' '
+		case high == low:
+			// A flat series would divide by zero, so draw it mid-height
+			line[i] = blocks[len(blocks)/2]
+		default:
+			level := int((v - low) / (high - low) * float64(len(blocks)-1))
+			line[i] = blocks[level]
+		}
+	}
+	return string(line)
+}
+
+// resample averages values into width buckets, skipping missing values
+func resample(values []float64, width int) []float64 {
+	buckets := make([]float64, width)
+	for b := range buckets {
+		start, end := b*len(values)/width, (b+1)*len(values)/width
+		sum, count := 0.0, 0
+		for _, v := range values[start:end] {
+			if !math.IsNaN(v) {
+				sum += v
+				count++
+			}
+		}
+		buckets[b] = math.NaN()
+		if count > 0 {
+			buckets[b] = sum / float64(count)
+		}
+	}
+	return buckets
+}
+
+// seriesRange returns the smallest and largest known value, and false when there are none
+func seriesRange(values []float64) (low, high float64, ok bool) {
+	low, high = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			low, high, ok = math.Min(low, v), math.Max(high, v), true
+		}
+	}
+	return low, high, ok
+}
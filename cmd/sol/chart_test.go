@@ -0,0 +1,36 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name    string
+		values  []float64
+		width   int
+		unicode string
+		ascii   string
+	}{
+		{"ramp", []float64{0, 1, 2, 3, 4, 5, 6, 7}, 0, "▁▂▃▄▅▆▇█", "_.-~=+*#"},
+		{"temperatures", []float64{12.4, 14.1, 18.9, 21.3, 19.0, 15.2}, 0, "▁▂▆█▆▃", "_.+#+-"},
+		{"flat", []float64{5, 5, 5, 5}, 0, "▅▅▅▅", "===="},
+		{"missing values", []float64{0, nan, 7, nan}, 0, "▁ █ ", "_ # "},
+		{"all missing", []float64{nan, nan}, 0, "  ", "  "},
+		{"empty", nil, 0, "", ""},
+		{"averaged into buckets", []float64{0, 0, 2, 4, 7, 7}, 3, "▁▄█", "_~#"},
+		{"wider than the values", []float64{0, 7}, 10, "▁█", "_#"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.values, tt.width, false); got != tt.unicode {
+				t.Errorf("sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.unicode)
+			}
+			if got := sparkline(tt.values, tt.width, true); got != tt.ascii {
+				t.Errorf("ASCII sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.ascii)
+			}
+		})
+	}
+}
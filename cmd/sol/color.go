@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ANSI escape sequences used when colors are on
+const (
+	ansiReset     = "\x1b[0m"
+	ansiHighlight = "\x1b[1;34m"
+	ansiRed       = "\x1b[31m"
+	ansiGreen     = "\x1b[32m"
+	ansiYellow    = "\x1b[33m"
+	ansiBlue      = "\x1b[34m"
+	ansiMagenta   = "\x1b[35m"
+	ansiCyan      = "\x1b[36m"
+)
+
+// useColor decides whether to write escape sequences to w for a -color mode.
+// In auto mode colors are used only when w is a terminal and never when NO_COLOR is set.
+func useColor(mode string, w io.Writer) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// paint wraps text in an escape sequence when colors are on
+func (opts renderOptions) paint(code, text string) string {
+	if !opts.Color || code == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// colorizeTemp returns the color for a temperature: blue below freezing, then
+// cyan, green and yellow in steps of 10°C, and red above 30°C
+func colorizeTemp(celsius float64) string {
+	switch {
+	case celsius < 0:
+		return ansiBlue
+	case celsius < 10:
+		return ansiCyan
+	case celsius < 20:
+		return ansiGreen
+	case celsius <= 30:
+		return ansiYellow
+	default:
+		return ansiRed
+	}
+}
+
+// temperatureColor picks the color for a temperature given in unit
+func temperatureColor(v float64, unit string) string {
+	if unit == "°F" {
+		v = (v - 32) * 5 / 9
+	}
+	return colorizeTemp(v)
+}
+
+// temperature formats a temperature like "12.3°C", padded to width and colored by value
+func (opts renderOptions) temperature(v *float64, unit string, width int) string {
+	text := fmt.Sprintf("%-*s", width, formatValue(v)+unit)
+	if v == nil {
+		return text
+	}
+	return opts.paint(temperatureColor(*v, unit), text)
+}
+
+// precipitationProbability highlights text when v reaches the precipitation threshold
+func (opts renderOptions) precipitationProbability(v *float64, text string) string {
+	if v == nil || *v < opts.PrecipitationThreshold {
+		return text
+	}
+	return opts.paint(ansiHighlight, text)
+}
+
+// windy shows a whole line in red when it reached the -wind-warn threshold
+func (opts renderOptions) windy(windy bool, text string) string {
+	if !windy {
+		return text
+	}
+	return opts.paint(ansiRed, text)
+}
+
+// uvColors follow the colors of the WHO UV index scale, with red standing in for orange
+var uvColors = map[string]string{
+	"Low":       ansiGreen,
+	"Moderate":  ansiYellow,
+	"High":      ansiRed,
+	"Very High": ansiRed,
+	"Extreme":   ansiMagenta,
+}
+
+// uvCategory colors a UV category, padded to width before the escape sequences are added
+func (opts renderOptions) uvCategory(category string, width int) string {
+	return opts.paint(uvColors[category], fmt.Sprintf("%-*s", width, category))
+}
+
+// windSpeed shows text in yellow when v is above the wind threshold
+func (opts renderOptions) windSpeed(v *float64, text string) string {
+	if v == nil || *v <= opts.WindThreshold {
+		return text
+	}
+	return opts.paint(ansiYellow, text)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// config holds the defaults applied before command line flags are parsed
+type config struct {
+	Latitude   float64
+	Longitude  float64
+	Days       int
+	Hours      int
+	Units      string
+	TimeFormat string
+	// Thresholds for highlighting in colored output
+	PrecipitationThreshold float64
+	WindThreshold          float64
+	// WindWarn is the wind or gust speed from which days and hours count as windy
+	WindWarn float64
+}
+
+// defaultConfig is used when there is no config file: New York City, 2 days, 5 hours
+func defaultConfig() config {
+	return config{
+		Latitude:   40.71,
+		Longitude:  -74.01,
+		Days:       2,
+		Hours:      5,
+		Units:      "metric",
+		TimeFormat: "24h",
+
+		PrecipitationThreshold: 60,
+		WindThreshold:          30,
+		WindWarn:               50,
+	}
+}
+
+// defaultConfigPath returns $XDG_CONFIG_HOME/sol/config.toml, falling back to ~/.config/sol/config.toml
+func defaultConfigPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "sol", "config.toml"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "sol", "config.toml"), nil
+}
+
+// configPathFromArgs finds a -config=<path> or -config <path> argument
+func configPathFromArgs(args []string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if value, ok := strings.CutPrefix(name, "config="); ok {
+			return value, true
+		}
+		if name == "config" && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// loadConfig reads the config file at path on top of the built-in defaults.
+// A missing file is not an error; found reports whether one was read.
+func loadConfig(path string) (cfg config, found bool, err error) {
+	cfg = defaultConfig()
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, false, nil
+	}
+	if err != nil {
+		return cfg, false, fmt.Errorf("error opening config file: %w", err)
+	}
+	defer file.Close()
+
+	if err := parseConfig(file, &cfg); err != nil {
+		return cfg, true, fmt.Errorf("error in config file %s: %w", path, err)
+	}
+	return cfg, true, nil
+}
+
+// parseConfig understands the small subset of TOML sol needs:
+// key = value lines with numbers or quoted strings, and # comments.
+func parseConfig(r io.Reader, cfg *config) error {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		key = strings.TrimSpace(key)
+
+		value, err := parseConfigValue(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("line %d: key %q: %w", lineNumber, key, err)
+		}
+
+		if err := cfg.set(key, value); err != nil {
+			return fmt.Errorf("line %d: key %q: %w", lineNumber, key, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseConfigValue strips quotes from strings and trailing comments from bare values
+func parseConfigValue(value string) (string, error) {
+	if strings.HasPrefix(value, `"`) {
+		end := strings.Index(value[1:], `"`)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		rest := strings.TrimSpace(value[end+2:])
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected text after string: %q", rest)
+		}
+		return value[1 : end+1], nil
+	}
+
+	if before, _, ok := strings.Cut(value, "#"); ok {
+		value = strings.TrimSpace(before)
+	}
+	if value == "" {
+		return "", fmt.Errorf("missing value")
+	}
+	return value, nil
+}
+
+// set assigns a single config key, checking the value the same way the flags are checked
+func (cfg *config) set(key, value string) error {
+	var err error
+	switch key {
+	case "latitude":
+		cfg.Latitude, err = strconv.ParseFloat(value, 64)
+	case "longitude":
+		cfg.Longitude, err = strconv.ParseFloat(value, 64)
+	case "days":
+		cfg.Days, err = strconv.Atoi(value)
+		if err == nil && (cfg.Days < 1 || cfg.Days > weather.MaxForecastDays) {
+			err = fmt.Errorf("must be between 1 and %d", weather.MaxForecastDays)
+		}
+	case "hours":
+		cfg.Hours, err = strconv.Atoi(value)
+		if err == nil && cfg.Hours < 0 {
+			err = fmt.Errorf("cannot be negative")
+		}
+	case "units":
+		if value != "metric" && value != "imperial" {
+			return fmt.Errorf("must be metric or imperial, got %q", value)
+		}
+		cfg.Units = value
+	case "time_format":
+		if value != "24h" && value != "12h" {
+			return fmt.Errorf("must be 24h or 12h, got %q", value)
+		}
+		cfg.TimeFormat = value
+	case "precipitation_threshold":
+		cfg.PrecipitationThreshold, err = strconv.ParseFloat(value, 64)
+	case "wind_threshold":
+		cfg.WindThreshold, err = strconv.ParseFloat(value, 64)
+	case "wind_warn":
+		cfg.WindWarn, err = strconv.ParseFloat(value, 64)
+	default:
+		return fmt.Errorf("unknown key")
+	}
+
+	if errors.Is(err, strconv.ErrSyntax) || errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("invalid number %q", value)
+	}
+	return err
+}
+
+// writeConfig saves cfg to path as a starting config file
+func writeConfig(path string, cfg config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating config directory: %w", err)
+	}
+
+	contents := fmt.Sprintf(`# sol configuration; command line flags override these values
+latitude = %s
+longitude = %s
+days = %d
+hours = %d
+units = %q
+time_format = %q
+precipitation_threshold = %s
+wind_threshold = %s
+wind_warn = %s
+`,
+		strconv.FormatFloat(cfg.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(cfg.Longitude, 'f', -1, 64),
+		cfg.Days, cfg.Hours, cfg.Units, cfg.TimeFormat,
+		strconv.FormatFloat(cfg.PrecipitationThreshold, 'f', -1, 64),
+		strconv.FormatFloat(cfg.WindThreshold, 'f', -1, 64),
+		strconv.FormatFloat(cfg.WindWarn, 'f', -1, 64))
+
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	return nil
+}
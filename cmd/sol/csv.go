@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1eemur/sol/weather"
+)
+
+var hourlyCSVHeader = []string{
+	"time", "temperature", "apparent_temperature", "precipitation",
+	"precipitation_probability", "weather_code", "relative_humidity", "dew_point",
+	"wind_speed", "wind_direction", "wind_gusts", "windy", "uv_index",
+	"snowfall", "snow_depth", "cloud_cover", "cloud_cover_low", "cloud_cover_mid", "cloud_cover_high",
+	"surface_pressure",
+}
+
+var dailyCSVHeader = []string{
+	"date", "temperature_min", "temperature_max", "apparent_temperature_min", "apparent_temperature_max",
+	"precipitation_sum", "precipitation_probability_max", "precipitation_probability_mean", "rain_sum", "precipitation_hours", "snowfall_sum", "snow_depth",
+	"wind_speed_max", "wind_gusts_max", "wind_direction", "weather_code", "sunrise", "sunset", "daylight_duration", "uv_index_max",
+	"daytime_relative_humidity", "night_cloud_cover", "windy",
+}
+
+// csvNumber writes floats without locale formatting; missing values are empty cells
+func csvNumber(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func csvCode(code *weather.WeatherCode) string {
+	if code == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*code))
+}
+
+// csvTime turns a local ISO time like "2024-06-03T05:00" into RFC 3339 with the zone's offset
+func csvTime(iso string, zone *time.Location) string {
+	if iso == "" {
+		return ""
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04", iso, zone)
+	if err != nil {
+		return iso
+	}
+	return t.Format(time.RFC3339)
+}
+
+// writeCSV writes one section ("hourly" or "daily") of the reports with a header row.
+// With several locations every row starts with the location's label.
+func writeCSV(w io.Writer, section string, labels []string, reports []Report) error {
+	header := hourlyCSVHeader
+	if section == "daily" {
+		header = dailyCSVHeader
+	}
+	multiple := len(reports) > 1
+	if multiple {
+		header = append([]string{"location"}, header...)
+	}
+
+	// The places and units go to -verbose so the output stays plain RFC 4180
+	if len(reports) > 0 {
+		units := reports[0].Units
+		debugf("CSV for %s; units: temperature %s, precipitation %s, wind %s, snowfall %s",
+			strings.Join(labels, " | "), units.Temperature, units.Precipitation, units.WindSpeed, units.Snowfall)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for i, report := range reports {
+		var rows [][]string
+		if section == "daily" {
+			for _, day := range report.Daily {
+				rows = append(rows, []string{
+					day.Date,
+					csvNumber(day.TemperatureMin), csvNumber(day.TemperatureMax),
+					csvNumber(day.ApparentTemperatureMin), csvNumber(day.ApparentTemperatureMax),
+					csvNumber(day.PrecipitationSum), csvNumber(day.PrecipitationProbabilityMax),
+					csvNumber(day.PrecipitationProbabilityMean),
+					csvNumber(day.RainSum), csvNumber(day.PrecipitationHours),
+					csvNumber(day.SnowfallSum), csvNumber(day.SnowDepth),
+					csvNumber(day.WindSpeedMax), csvNumber(day.WindGustsMax), csvNumber(day.WindDirection), csvCode(day.WeatherCode),
+					csvTime(day.Sunrise, report.Zone), csvTime(day.Sunset, report.Zone),
+					csvNumber(day.DaylightDuration), csvNumber(day.UVIndexMax),
+					csvNumber(day.DaytimeHumidity), csvNumber(day.NightCloudCover), strconv.FormatBool(day.Windy),
+				})
+			}
+		} else {
+			for _, hour := range report.Hourly {
+				rows = append(rows, []string{
+					csvTime(hour.Time, report.Zone),
+					csvNumber(hour.Temperature), csvNumber(hour.ApparentTemperature),
+					csvNumber(hour.Precipitation), csvNumber(hour.PrecipitationProbability),
+					csvCode(hour.WeatherCode), csvNumber(hour.RelativeHumidity),
+					csvNumber(hour.DewPoint), csvNumber(hour.WindSpeed), csvNumber(hour.WindDirection),
+					csvNumber(hour.WindGusts), strconv.FormatBool(hour.Windy), csvNumber(hour.UVIndex),
+					csvNumber(hour.Snowfall), csvNumber(hour.SnowDepth), csvNumber(hour.CloudCover),
+					csvNumber(hour.CloudCoverLow), csvNumber(hour.CloudCoverMid), csvNumber(hour.CloudCoverHigh),
+					csvNumber(hour.SurfacePressure),
+				})
+			}
+		}
+
+		for _, row := range rows {
+			if multiple {
+				row = append([]string{labels[i]}, row...)
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSVIsPlain(t *testing.T) {
+	report := Report{Units: ReportUnits{Temperature: "°C", Precipitation: "mm", WindSpeed: "km/h", Snowfall: "cm"}}
+	var out strings.Builder
+	if err := writeCSV(&out, "daily", []string{"Berlin"}, []Report{report}); err != nil {
+		t.Fatal(err)
+	}
+	// Without Comment set, encoding/csv reads a metadata line as a record
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("reading the CSV back: %v\n%s", err, out.String())
+	}
+	if len(records) != 1 || strings.Join(records[0], ",") != strings.Join(dailyCSVHeader, ",") {
+		t.Errorf("CSV without days = %q, want only the daily header", records)
+	}
+}
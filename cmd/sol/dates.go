@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// dateLayout is how -date and -end-date are written and sent to the API
+const dateLayout = "2006-01-02"
+
+// parseDate reads a -date value: YYYY-MM-DD, or "today", "yesterday" and "tomorrow"
+// relative to today
+func parseDate(value string, today time.Time) (time.Time, error) {
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date %q must be YYYY-MM-DD, today, yesterday or tomorrow", value)
+	}
+	return date, nil
+}
+
+// dateSpan checks the -date and -end-date values and returns the span to request
+// with the number of days in it. Days before the forecast API's window of
+// MaxPastDays come from the archive, which ends yesterday.
+func dateSpan(startValue, endValue string, today time.Time) (weather.Options, int, error) {
+	start, err := parseDate(startValue, today)
+	if err != nil {
+		return weather.Options{}, 0, err
+	}
+	end := start
+	if endValue != "" {
+		if end, err = parseDate(endValue, today); err != nil {
+			return weather.Options{}, 0, err
+		}
+	}
+
+	earliest, _ := time.Parse(dateLayout, weather.ArchiveStart)
+	latest := today.AddDate(0, 0, weather.MaxForecastDays-1)
+	switch {
+	case start.Before(earliest):
+		return weather.Options{}, 0, fmt.Errorf("%s is before %s, where the historical record starts", start.Format(dateLayout), weather.ArchiveStart)
+	case end.After(latest):
+		return weather.Options{}, 0, fmt.Errorf("%s is too far in the future, the forecast reaches %s", end.Format(dateLayout), latest.Format(dateLayout))
+	case end.Before(start):
+		return weather.Options{}, 0, fmt.Errorf("end date %s is before the start date %s", end.Format(dateLayout), start.Format(dateLayout))
+	}
+
+	opts := weather.Options{StartDate: start.Format(dateLayout), EndDate: end.Format(dateLayout)}
+	if start.Before(today.AddDate(0, 0, -weather.MaxPastDays)) {
+		if !end.Before(today) {
+			return weather.Options{}, 0, fmt.Errorf("dates more than %d days ago come from the archive, so the span has to end by yesterday", weather.MaxPastDays)
+		}
+		opts.Archive = true
+	}
+	return opts, int(end.Sub(start).Hours()/24) + 1, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import "sort"
+
+// locales translate the labels of the text output, keyed by their English text.
+// English is the default, and missing entries fall back to it, as "UV" does.
+// Weather descriptions, compass points, advice and UV categories stay in English.
+var locales = map[string]map[string]string{
+	"de": {
+		"Weather for": "Wetter für", "Timezone": "Zeitzone", "Now": "Jetzt",
+		"humidity": "Luftfeuchtigkeit", "wind": "Wind", "from": "aus", "feels like": "gefühlt",
+		"Today": "Heute", "Tomorrow": "Morgen", "Yesterday": "Gestern", "%d days ago": "vor %d Tagen",
+		"Monday": "Montag", "Tuesday": "Dienstag", "Wednesday": "Mittwoch", "Thursday": "Donnerstag",
+		"Friday": "Freitag", "Saturday": "Samstag", "Sunday": "Sonntag",
+		"Temperature": "Temperatur", "to": "bis", "Precipitation": "Niederschlag",
+		"probability": "Wahrscheinlichkeit", "max": "max", "mean": "Mittel",
+		"Rain": "Regen", "Precipitation Hours": "Niederschlagsstunden",
+		"Max Wind Speed": "Max. Windgeschwindigkeit", "gusts": "Böen",
+		"Snowfall": "Schneefall", "Snow Depth": "Schneehöhe", "Max UV Index": "Max. UV-Index",
+		"Daytime Humidity": "Luftfeuchtigkeit tagsüber", "Sunrise": "Sonnenaufgang",
+		"Sunset": "Sonnenuntergang", "daylight": "Tageslicht",
+		"Humidity": "Feuchte", "Clouds": "Wolken", "Dew point": "Taupunkt", "Wind": "Wind", "Pressure": "Luftdruck",
+		"Air quality": "Luftqualität", "ozone": "Ozon", "Worst hour today": "Schlechteste Stunde heute",
+		"Pollen": "Pollenflug", "pollen data not available for this location": "keine Pollendaten für diesen Ort",
+		"Sea": "Seegang", "waves up to": "Wellen bis", "period": "Periode", "swell": "Dünung",
+		"Sea state: inland, no marine forecast for this location":                        "Seegang: im Binnenland, keine Meeresvorhersage für diesen Ort",
+		"Clear night for stargazing (%.0f%% cloud cover from 22:00 to 02:00)":            "Klare Nacht zum Sternegucken (%.0f%% Bewölkung von 22:00 bis 02:00)",
+		"No sunrise or sunset (polar day)":                                               "Kein Sonnenaufgang oder -untergang (Polartag)",
+		"No sunrise or sunset (polar night)":                                             "Kein Sonnenaufgang oder -untergang (Polarnacht)",
+		"the forecast":                                                                   "der Vorhersage",
+		"Not available from %s here: %s":                                                 "Hier nicht verfügbar in %s: %s",
+		"Incomplete in the API response, shown as n/a: %s":                               "Unvollständig in der API-Antwort, als n/a angezeigt: %s",
+		"Ensemble ranges from %s, on a coarser grid than the forecast":                   "Ensemble-Spannen von %s, auf einem gröberen Raster als die Vorhersage",
+		"High: %s%s (%.0f–%.0f%s across %d members), precipitation in %.0f%% of members": "Höchstwert: %s%s (%.0f–%.0f%s über %d Mitglieder), Niederschlag in %.0f%% der Mitglieder",
+		"Good": "Gut", "Fair": "Ausreichend", "Moderate": "Mäßig", "Poor": "Schlecht", "Very poor": "Sehr schlecht",
+		"Extremely poor": "Äußerst schlecht", "Unhealthy for sensitive groups": "Ungesund für empfindliche Gruppen",
+		"Unhealthy": "Ungesund", "Very unhealthy": "Sehr ungesund", "Hazardous": "Gefährlich",
+		"Low": "Gering", "Medium": "Mittel", "High": "Hoch", "grains/m³": "Pollen/m³",
+		"US AQI": "US-AQI", "European AQI": "Europäischer AQI", "precip": "Nieders.",
+		"Next %d hours": "Nächste %d Stunden", "Low/Mid/High": "Tief/Mittel/Hoch",
+		"Hourly Forecast (next %d hours)":                                 "Stündliche Vorhersage (nächste %d Stunden)",
+		"Hourly Weather (%d hours)":                                       "Stündliches Wetter (%d Stunden)",
+		"Hourly Forecast for %s":                                          "Stündliche Vorhersage für %s",
+		"(%.1f %s total)":                                                 "(%.1f %s insgesamt)",
+		"No rain expected in the next %d hours":                           "Kein Regen in den nächsten %d Stunden erwartet",
+		"Raining now and for at least the next %d hours %s":               "Regen jetzt und mindestens in den nächsten %d Stunden %s",
+		"Raining now, ending around %s %s":                                "Regen jetzt, endet gegen %s %s",
+		"Rain starting around %s and lasting beyond the next %d hours %s": "Regen ab etwa %s, über die nächsten %d Stunden hinaus %s",
+		"Rain starting around %s, ending around %s %s":                    "Regen ab etwa %s, endet gegen %s %s",
+		"Best %dh window for outdoor plans":                               "Bestes %dh-Fenster für Unternehmungen im Freien",
+		"none in the next %d hours":                                       "keines in den nächsten %d Stunden",
+		"dry":                                                             "trocken",
+		"up to %.0f%% chance of precipitation":                            "bis zu %.0f%% Niederschlagswahrscheinlichkeit",
+		"light wind":                                                      "schwacher Wind",
+		"breezy":                                                          "frisch",
+		"windy":                                                           "stürmisch",
+		"FROST WARNING: low of %s%s":                                      "FROSTWARNUNG: Tiefstwert %s%s",
+	},
+	"fr": {
+		"Weather for": "Météo pour", "Timezone": "Fuseau horaire", "Now": "Maintenant",
+		"humidity": "humidité", "wind": "vent", "from": "du", "feels like": "ressenti",
+		"Today": "Aujourd'hui", "Tomorrow": "Demain", "Yesterday": "Hier", "%d days ago": "Il y a %d jours",
+		"Monday": "Lundi", "Tuesday": "Mardi", "Wednesday": "Mercredi", "Thursday": "Jeudi",
+		"Friday": "Vendredi", "Saturday": "Samedi", "Sunday": "Dimanche",
+		"Temperature": "Température", "to": "à", "Precipitation": "Précipitations",
+		"probability": "probabilité", "max": "max", "mean": "moyenne",
+		"Rain": "Pluie", "Precipitation Hours": "Heures de précipitations",
+		"Max Wind Speed": "Vent max", "gusts": "rafales",
+		"Snowfall": "Chutes de neige", "Snow Depth": "Hauteur de neige", "Max UV Index": "Indice UV max",
+		"Daytime Humidity": "Humidité en journée", "Sunrise": "Lever du soleil",
+		"Sunset": "Coucher du soleil", "daylight": "de jour",
+		"Humidity": "Humidité", "Clouds": "Nuages", "Dew point": "Point de rosée", "Wind": "Vent", "Pressure": "Pression",
+		"Air quality": "Qualité de l'air", "ozone": "ozone", "Worst hour today": "Pire heure aujourd'hui",
+		"Pollen": "Pollen", "pollen data not available for this location": "pas de données de pollen pour ce lieu",
+		"Sea": "Mer", "waves up to": "vagues jusqu'à", "period": "période", "swell": "houle",
+		"Sea state: inland, no marine forecast for this location":                        "État de la mer: à l'intérieur des terres, pas de prévisions marines pour ce lieu",
+		"Clear night for stargazing (%.0f%% cloud cover from 22:00 to 02:00)":            "Nuit claire pour observer les étoiles (%.0f%% de nuages de 22:00 à 02:00)",
+		"No sunrise or sunset (polar day)":                                               "Ni lever ni coucher du soleil (jour polaire)",
+		"No sunrise or sunset (polar night)":                                             "Ni lever ni coucher du soleil (nuit polaire)",
+		"the forecast":                                                                   "la prévision",
+		"Not available from %s here: %s":                                                 "Non disponible ici dans %s: %s",
+		"Incomplete in the API response, shown as n/a: %s":                               "Incomplet dans la réponse de l'API, affiché comme n/a: %s",
+		"Ensemble ranges from %s, on a coarser grid than the forecast":                   "Plages de l'ensemble %s, sur une grille plus grossière que la prévision",
+		"High: %s%s (%.0f–%.0f%s across %d members), precipitation in %.0f%% of members": "Max: %s%s (%.0f–%.0f%s sur %d membres), précipitations dans %.0f%% des membres",
+		"Good": "Bon", "Fair": "Correct", "Moderate": "Moyen", "Poor": "Mauvais", "Very poor": "Très mauvais",
+		"Extremely poor": "Extrêmement mauvais", "Unhealthy for sensitive groups": "Malsain pour les groupes sensibles",
+		"Unhealthy": "Malsain", "Very unhealthy": "Très malsain", "Hazardous": "Dangereux",
+		"Low": "Faible", "Medium": "Moyen", "High": "Élevé", "grains/m³": "grains/m³",
+		"US AQI": "IQA US", "European AQI": "IQA européen", "precip": "précip.",
+		"Next %d hours": "%d prochaines heures", "Low/Mid/High": "Bas/Moyen/Haut",
+		"Hourly Forecast (next %d hours)":                                 "Prévisions horaires (%d prochaines heures)",
+		"Hourly Weather (%d hours)":                                       "Météo horaire (%d heures)",
+		"Hourly Forecast for %s":                                          "Prévisions horaires pour %s",
+		"(%.1f %s total)":                                                 "(%.1f %s au total)",
+		"No rain expected in the next %d hours":                           "Pas de pluie prévue dans les %d prochaines heures",
+		"Raining now and for at least the next %d hours %s":               "Pluie en ce moment et pendant au moins les %d prochaines heures %s",
+		"Raining now, ending around %s %s":                                "Pluie en ce moment, jusque vers %s %s",
+		"Rain starting around %s and lasting beyond the next %d hours %s": "Pluie à partir de %s environ, au-delà des %d prochaines heures %s",
+		"Rain starting around %s, ending around %s %s":                    "Pluie à partir de %s environ, jusque vers %s %s",
+		"Best %dh window for outdoor plans":                               "Meilleur créneau de %dh pour sortir",
+		"none in the next %d hours":                                       "aucun dans les %d prochaines heures",
+		"dry":                                                             "sec",
+		"up to %.0f%% chance of precipitation":                            "jusqu'à %.0f%% de risque de précipitations",
+		"light wind":                                                      "vent faible",
+		"breezy":                                                          "venteux",
+		"windy":                                                           "vent fort",
+		"FROST WARNING: low of %s%s":                                      "ALERTE GEL: minimum de %s%s",
+	},
+	"es": {
+		"Weather for": "Tiempo para", "Timezone": "Zona horaria", "Now": "Ahora",
+		"humidity": "humedad", "wind": "viento", "from": "del", "feels like": "sensación",
+		"Today": "Hoy", "Tomorrow": "Mañana", "Yesterday": "Ayer", "%d days ago": "Hace %d días",
+		"Monday": "Lunes", "Tuesday": "Martes", "Wednesday": "Miércoles", "Thursday": "Jueves",
+		"Friday": "Viernes", "Saturday": "Sábado", "Sunday": "Domingo",
+		"Temperature": "Temperatura", "to": "a", "Precipitation": "Precipitación",
+		"probability": "probabilidad", "max": "máx", "mean": "media",
+		"Rain": "Lluvia", "Precipitation Hours": "Horas de precipitación",
+		"Max Wind Speed": "Viento máx.", "gusts": "ráfagas",
+		"Snowfall": "Nevada", "Snow Depth": "Espesor de nieve", "Max UV Index": "Índice UV máx.",
+		"Daytime Humidity": "Humedad diurna", "Sunrise": "Amanecer",
+		"Sunset": "Atardecer", "daylight": "de luz",
+		"Humidity": "Humedad", "Clouds": "Nubes", "Dew point": "Punto de rocío", "Wind": "Viento", "Pressure": "Presión",
+		"Air quality": "Calidad del aire", "ozone": "ozono", "Worst hour today": "Peor hora de hoy",
+		"Pollen": "Polen", "pollen data not available for this location": "sin datos de polen para este lugar",
+		"Sea": "Mar", "waves up to": "olas de hasta", "period": "periodo", "swell": "mar de fondo",
+		"Sea state: inland, no marine forecast for this location":                        "Estado del mar: interior, sin pronóstico marino para este lugar",
+		"Clear night for stargazing (%.0f%% cloud cover from 22:00 to 02:00)":            "Noche despejada para ver las estrellas (%.0f%% de nubosidad de 22:00 a 02:00)",
+		"No sunrise or sunset (polar day)":                                               "Sin amanecer ni atardecer (día polar)",
+		"No sunrise or sunset (polar night)":                                             "Sin amanecer ni atardecer (noche polar)",
+		"the forecast":                                                                   "el pronóstico",
+		"Not available from %s here: %s":                                                 "No disponible aquí en %s: %s",
+		"Incomplete in the API response, shown as n/a: %s":                               "Incompleto en la respuesta de la API, se muestra como n/a: %s",
+		"Ensemble ranges from %s, on a coarser grid than the forecast":                   "Rangos del conjunto %s, en una malla más gruesa que el pronóstico",
+		"High: %s%s (%.0f–%.0f%s across %d members), precipitation in %.0f%% of members": "Máx.: %s%s (%.0f–%.0f%s en %d miembros), precipitación en el %.0f%% de los miembros",
+		"Good": "Buena", "Fair": "Razonable", "Moderate": "Moderada", "Poor": "Mala", "Very poor": "Muy mala",
+		"Extremely poor": "Extremadamente mala", "Unhealthy for sensitive groups": "Dañina para grupos sensibles",
+		"Unhealthy": "Dañina", "Very unhealthy": "Muy dañina", "Hazardous": "Peligrosa",
+		"Low": "Bajo", "Medium": "Medio", "High": "Alto", "grains/m³": "granos/m³",
+		"US AQI": "ICA de EE. UU.", "European AQI": "ICA europeo", "precip": "precip.",
+		"Next %d hours": "Próximas %d horas", "Low/Mid/High": "Bajo/Medio/Alto",
+		"Hourly Forecast (next %d hours)":                                 "Pronóstico por horas (próximas %d horas)",
+		"Hourly Weather (%d hours)":                                       "Tiempo por horas (%d horas)",
+		"Hourly Forecast for %s":                                          "Pronóstico por horas para %s",
+		"(%.1f %s total)":                                                 "(%.1f %s en total)",
+		"No rain expected in the next %d hours":                           "No se espera lluvia en las próximas %d horas",
+		"Raining now and for at least the next %d hours %s":               "Lloviendo ahora y al menos durante las próximas %d horas %s",
+		"Raining now, ending around %s %s":                                "Lloviendo ahora, hasta alrededor de las %s %s",
+		"Rain starting around %s and lasting beyond the next %d hours %s": "Lluvia desde alrededor de las %s y más allá de las próximas %d horas %s",
+		"Rain starting around %s, ending around %s %s":                    "Lluvia desde alrededor de las %s hasta alrededor de las %s %s",
+		"Best %dh window for outdoor plans":                               "Mejor franja de %dh para planes al aire libre",
+		"none in the next %d hours":                                       "ninguna en las próximas %d horas",
+		"dry":                                                             "seco",
+		"up to %.0f%% chance of precipitation":                            "hasta %.0f%% de probabilidad de precipitación",
+		"light wind":                                                      "viento suave",
+		"breezy":                                                          "ventoso",
+		"windy":                                                           "viento fuerte",
+		"FROST WARNING: low of %s%s":                                      "AVISO DE HELADA: mínima de %s%s",
+	},
+}
+
+// localeNames lists the accepted -locale values
+func localeNames() []string {
+	names := []string{"en"}
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// tr translates an English label into the -locale language, leaving it as is when there is no translation
+func (opts renderOptions) tr(english string) string {
+	if translated, ok := locales[opts.Locale][english]; ok {
+		return translated
+	}
+	return english
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// defaultConcurrency is how many locations are fetched at once unless -concurrency
+// says otherwise; maxConcurrency keeps long lists within the API's fair use
+const (
+	defaultConcurrency = 4
+	maxConcurrency     = 16
+)
+
+// location is a place to fetch a forecast for. When Query is set the
+// coordinates are looked up by name before fetching.
+type location struct {
+	Name      string
+	Query     string
+	Latitude  float64
+	Longitude float64
+}
+
+// label returns the place name, or the coordinates when there is none
+func (l location) label() string {
+	if l.Name != "" {
+		return l.Name
+	}
+	if l.Query != "" {
+		return l.Query
+	}
+	return fmt.Sprintf("%.4f, %.4f", l.Latitude, l.Longitude)
+}
+
+// repeatedFlag collects every value of a flag that is given more than once
+type repeatedFlag []string
+
+func (r *repeatedFlag) String() string {
+	return strings.Join(*r, ",")
+}
+
+func (r *repeatedFlag) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
+// readCitiesFile reads the city names of a -cities-file, one per line.
+// Blank lines and lines starting with # are skipped.
+func readCitiesFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cities file: %w", err)
+	}
+
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("cities file %s lists no cities", path)
+	}
+	return names, nil
+}
+
+// parseLocations reads a list like "40.71,-74.01;51.5,-0.12"
+func parseLocations(value string) ([]location, error) {
+	var locations []location
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		lat, lon, ok := strings.Cut(entry, ",")
+		if !ok {
+			return nil, fmt.Errorf("location %q must be in the form lat,lon", entry)
+		}
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude in %q: %w", entry, err)
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude in %q: %w", entry, err)
+		}
+		if err := weather.ValidateCoordinates(latitude, longitude); err != nil {
+			return nil, fmt.Errorf("location %q: %w", entry, err)
+		}
+		locations = append(locations, location{Latitude: latitude, Longitude: longitude})
+	}
+
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("no locations given")
+	}
+	return locations, nil
+}
+
+type forecastResult struct {
+	Location location
+	Response *weather.WeatherResponse
+	// AirQuality and Pollen are set when they were asked for and could be fetched
+	AirQuality *weather.AirQualityResponse
+	Pollen     *weather.PollenResponse
+	// Marine is set with -marine; an error from the marine API is kept in MarineErr
+	Marine    *weather.MarineResponse
+	MarineErr error
+	Ensemble  *weather.EnsembleResponse
+	Err       error
+}
+
+// fetchExtras selects what is fetched along with each forecast
+type fetchExtras struct {
+	AirQuality bool
+	Pollen     bool
+	Marine     bool
+	Ensemble   bool
+}
+
+// maxRoundDecimals is the most -round accepts; the API uses no finer coordinates
+const maxRoundDecimals = 6
+
+// roundCoordinate rounds a latitude or longitude to the given number of decimal places
+func roundCoordinate(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
+
+// fetchForecasts looks up and fetches every location using a pool of at most workers goroutines,
+// with the coordinates of each location filled into opts, along with the extras.
+// Results keep the order of locations and a failure only affects its own entry;
+// missing extras only lead to a warning.
+func fetchForecasts(ctx context.Context, client *weather.Client, locations []location, workers int, opts weather.Options, extras fetchExtras) []forecastResult {
+	results := make([]forecastResult, len(locations))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(locations)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				loc := locations[i]
+				if loc.Query != "" {
+					place, err := client.GeocodeLocation(ctx, loc.Query)
+					if err != nil {
+						results[i] = forecastResult{Location: loc, Err: fmt.Errorf("error looking up city %q: %w", loc.Query, err)}
+						continue
+					}
+					loc.Latitude, loc.Longitude, loc.Name = place.Latitude, place.Longitude, place.Name
+				}
+
+				opts := opts
+				opts.Latitude, opts.Longitude = loc.Latitude, loc.Longitude
+				response, err := client.Forecast(ctx, opts)
+				results[i] = forecastResult{Location: loc, Response: response, Err: err}
+				if err != nil {
+					continue
+				}
+
+				if extras.AirQuality {
+					results[i].AirQuality, err = client.AirQuality(ctx, loc.Latitude, loc.Longitude)
+					if err != nil {
+						logf("Warning: could not get the air quality for %s: %v", loc.label(), err)
+					}
+				}
+				if extras.Pollen {
+					results[i].Pollen, err = client.Pollen(ctx, loc.Latitude, loc.Longitude)
+					if err != nil {
+						logf("Warning: could not get the pollen forecast for %s: %v", loc.label(), err)
+					}
+				}
+				if extras.Marine {
+					results[i].Marine, results[i].MarineErr = client.Marine(ctx, opts)
+				}
+				if extras.Ensemble {
+					results[i].Ensemble, err = client.Ensemble(ctx, opts)
+					if err != nil {
+						logf("Warning: could not get the ensemble forecast for %s: %v", loc.label(), err)
+					}
+				}
+			}
+		}()
+	}
+
+	for i := range locations {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
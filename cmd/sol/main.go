@@ -0,0 +1,1072 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// version is reported by -version and in the User-Agent header.
+// Release builds set it with: go build -ldflags "-X main.version=1.2.3" ./cmd/sol
+var version = "dev"
+
+// formats lists the accepted -format values. template is picked for a -format
+// holding a Go template and can also be given with -format-file
+var formats = []string{"text", "json", "oneline", "summary", "waybar", "csv", "metrics", "template"}
+
+// Exit codes, so scripts can tell bad input from an unreachable API
+const (
+	exitOK = 0
+	// exitUsage is for invalid flags, coordinates, config values and unknown places
+	exitUsage = 1
+	// exitNetwork is for network problems and API errors, including rate limits
+	exitNetwork = 2
+	// exitResponse is for API responses that could not be parsed
+	exitResponse = 3
+	// exitFailure covers everything else, such as unwritable files
+	exitFailure = 4
+	// exitAlert is for -alert conditions that match the forecast
+	exitAlert = 5
+	// exitNotForecast is -check's answer when the condition is not forecast
+	exitNotForecast = 6
+)
+
+// exitCode picks the exit code for a failed forecast
+func exitCode(err error) int {
+	var decodeErr *weather.DecodeError
+	switch {
+	case errors.As(err, &decodeErr):
+		return exitResponse
+	case errors.Is(err, weather.ErrLocationNotFound):
+		return exitUsage
+	default:
+		return exitNetwork
+	}
+}
+
+// diagnostics receives notes and warnings; it is discarded with -quiet, for status
+// bar formats and for -check and -alert, which promise to print nothing else
+var diagnostics io.Writer = os.Stderr
+
+// verbose receives progress details; it is discarded unless -verbose is given
+var verbose io.Writer = io.Discard
+
+// logf writes one line of informational output such as notes and warnings
+func logf(format string, args ...any) {
+	fmt.Fprintf(diagnostics, format+"\n", args...)
+}
+
+// debugf writes one line of progress output for -verbose
+func debugf(format string, args ...any) {
+	fmt.Fprintf(verbose, format+"\n", args...)
+}
+
+// envOr returns the environment variable key, or fallback when it is unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// validateBaseURL checks that an endpoint override is a full http(s) URL without a query
+func validateBaseURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must start with http:// or https://", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", raw)
+	}
+	if u.RawQuery != "" {
+		return fmt.Errorf("%q must not contain query parameters", raw)
+	}
+	return nil
+}
+
+func main() {
+	// Defaults come from the config file when there is one. Its path has to be
+	// known before the flags are defined, so -config is picked out by hand.
+	configPath, configGiven := configPathFromArgs(os.Args[1:])
+	if !configGiven {
+		var err error
+		configPath, err = defaultConfigPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailure)
+		}
+	}
+	cfg, configFound, err := loadConfig(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	client := weather.NewClient()
+	client.UserAgent = "sol/" + version
+
+	// Set up command line flags; flag errors exit with exitUsage instead of the flag package's 2
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	flag.String("config", configPath, "Path of the config file")
+	latitude := flag.Float64("lat", cfg.Latitude, "Latitude (default: New York City)")
+	longitude := flag.Float64("lon", cfg.Longitude, "Longitude (default: New York City)")
+	roundTo := flag.Int("round", -1, "Round the coordinates to this many decimal places before they are sent or cached, e.g. 2 for about 1 km (default: full precision)")
+	days := flag.Int("days", cfg.Days, "Number of days to show (default: 2; max: 16)")
+	pastDays := flag.Int("past-days", 0, "Number of past days to show before today (max: 92)")
+	date := flag.String("date", "", "Show this day instead of the forecast from today: YYYY-MM-DD, yesterday or tomorrow (from 1940)")
+	endDate := flag.String("end-date", "", "Last day of a span started with -date")
+	hours := flag.Int("hours", cfg.Hours, "Number of hourly forecast rows to show (default: 5)")
+	hourlyDay := flag.Int("hourly-day", -1, "Show the 24 hours of this day instead of the hours from now, 0 being today and 1 tomorrow")
+	noDaily := flag.Bool("no-daily", false, "Leave out the daily forecast")
+	noHourly := flag.Bool("no-hourly", false, "Leave out the hourly forecast")
+	compact := flag.Bool("compact", false, "Print each day of the daily forecast on a single line")
+	locale := flag.String("locale", "en", "Language of the text output labels: "+strings.Join(localeNames(), ", "))
+	model := flag.String("model", "", "Weather model to use instead of the best match, e.g. icon_seamless (see -list-models)")
+	listModels := flag.Bool("list-models", false, "List the weather models -model accepts and exit")
+	var cities, locNames repeatedFlag
+	flag.Var(&cities, "city", "City name to look up instead of -lat/-lon (may be repeated)")
+	flag.Var(&locNames, "loc", "Name of a saved location to use (may be repeated or comma separated)")
+	saveLocation := flag.String("save-location", "", "Save the location used in this run under a name")
+	listLocations := flag.Bool("list-locations", false, "List saved locations and exit")
+	deleteLocation := flag.String("delete-location", "", "Delete a saved location and exit")
+	locationList := flag.String("locations", "", "Several locations as \"lat,lon;lat,lon\"")
+	citiesFile := flag.String("cities-file", "", "File with one city name per line to show, like repeated -city")
+	concurrency := flag.Int("concurrency", defaultConcurrency, "How many locations to look up and fetch at once")
+	units := flag.String("units", cfg.Units, "Unit system: metric or imperial")
+	format := flag.String("format", "text", "Output format: text, json, oneline, summary, waybar, csv, metrics or a Go template such as '{{.Current.Temp}}'")
+	formatFile := flag.String("format-file", "", "Read the output template from a file")
+	csvSection := flag.String("csv-section", "hourly", "Rows written by -format=csv: hourly or daily")
+	outputPath := flag.String("output", "", "Write the forecast to this file instead of standard output")
+	jsonOutput := flag.Bool("json", false, "Shorthand for -format=json")
+	pretty := flag.Bool("pretty", false, "Indent the JSON output of -format=json for reading")
+	oneline := flag.Bool("oneline", false, "Shorthand for -format=oneline, a single line for status bars")
+	summary := flag.Bool("summary", false, "Shorthand for -format=summary, one plain line like \"New York: 18°C, slight rain, 40% precip, wind 12 km/h NE\"")
+	metrics := flag.Bool("metrics", false, "Shorthand for -format=metrics, the current conditions in the Prometheus text format")
+	timeFormat := flag.String("time-format", cfg.TimeFormat, "Clock format for times: 24h or 12h")
+	chart := flag.Bool("chart", false, "Draw a temperature sparkline above the hourly forecast")
+	graph := flag.Bool("graph", false, "Draw the temperature and precipitation trend of the next 24 hours")
+	detail := flag.Bool("detail", false, "Show the dew point, wind, daytime UV index, cloud layers and surface pressure in the hourly forecast")
+	flag.BoolVar(detail, "detailed", false, "Same as -detail")
+	ascii := flag.Bool("ascii", false, "Draw sparklines with ASCII characters only")
+	noEmoji := flag.Bool("no-emoji", false, "Do not print weather icons")
+	colorMode := flag.String("color", "auto", "Color output: auto, always or never (auto honors NO_COLOR)")
+	precipitationThreshold := flag.Float64("precipitation-threshold", cfg.PrecipitationThreshold, "Highlight precipitation probabilities from this percentage")
+	windThreshold := flag.Float64("wind-threshold", cfg.WindThreshold, "Highlight wind speeds above this value")
+	bestWindow := flag.Duration("best-window", 0, "Recommend the best window of this many whole hours for outdoor plans in the next 48 hours, e.g. 2h")
+	comfort := flag.String("comfort", "", "Comfortable temperatures for -best-window as min,max (default: 18,24 in °C or 64,75 in °F)")
+	daylightOnly := flag.Bool("daylight-only", false, "Only consider daylight hours for -best-window")
+	frostThreshold := flag.Float64("frost-threshold", 0, "Warn of frost on days whose low is below this temperature (default: 0°C or 32°F)")
+	check := flag.String("check", "", "Print nothing and exit with 0 when the condition is forecast for today or the coming days and 6 when not; the only condition is frost")
+	alert := flag.String("alert", "", "Print a line and exit with 5 when the day matches conditions like 'temp_max>35 || precip_prob>80', otherwise print nothing and exit with 0")
+	alertDay := flag.Int("alert-day", 0, "Day the -alert conditions are checked for, 0 being today and 1 tomorrow")
+	rainThreshold := flag.Float64("rain-threshold", 50, "Probability in percent from which an hour with precipitation counts as rainy in the rain outlook")
+	windWarn := flag.Float64("wind-warn", cfg.WindWarn, "Mark days and hours whose wind or gusts reach this speed as windy (0 turns it off)")
+	fromNextHour := flag.Bool("from-next-hour", false, "Start the hourly forecast at the next full hour instead of the current one")
+	airQuality := flag.Bool("air-quality", false, "Also fetch and show the current air quality (PM2.5, PM10, ozone, US and European AQI)")
+	flag.BoolVar(airQuality, "aqi", false, "Same as -air-quality")
+	pollen := flag.Bool("pollen", false, "Also fetch and show the pollen species above Low (Europe only)")
+	marine := flag.Bool("marine", false, "Also fetch and show the daily sea state: wave height, direction and period")
+	ensemble := flag.Bool("ensemble", false, "Also fetch the ensemble forecast and show the spread of daily highs and precipitation (large download)")
+	nowOnly := flag.Bool("now", false, "Print only the current conditions and exit")
+	cacheTTL := flag.Duration("cache-ttl", 15*time.Minute, "How long cached forecasts stay fresh")
+	noCache := flag.Bool("no-cache", false, "Do not read or write the forecast cache")
+	maxStale := flag.Duration("max-stale", 24*time.Hour, "How old a cached forecast may be when the API cannot be reached")
+	timeout := flag.Duration("timeout", client.HTTP.Timeout, "Maximum time to wait for each request")
+	apiURL := flag.String("api-url", envOr("SOL_API_URL", ""), "Base URL of a self-hosted Open-Meteo server for all endpoints, or the full URL of a forecast endpoint (env: SOL_API_URL)")
+	apiKey := flag.String("apikey", "", "API key of a commercial Open-Meteo plan (env: SOL_API_KEY)")
+	dryRun := flag.Bool("dry-run", false, "Print the forecast request URLs, with any API key masked, and exit without fetching")
+	retries := flag.Int("retries", client.Retries, "How many times to retry failed requests")
+	saveConfig := flag.Bool("write-config", false, "Write the current settings to the config file and exit")
+	showVersion := flag.Bool("version", false, "Print the version and exit")
+	var watch watchFlag
+	flag.Var(&watch, "watch", "Clear the screen and refresh the forecast every -interval until interrupted")
+	interval := flag.Duration("interval", 10*time.Minute, "How often -watch refreshes the forecast (at least 1m)")
+	quiet := flag.Bool("quiet", false, "Print only the forecast, without notes and warnings")
+	verboseOutput := flag.Bool("verbose", false, "Print progress details such as retries, cache hits and the current hourly slot to stderr")
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(exitUsage)
+	}
+
+	if *showVersion {
+		fmt.Printf("sol %s\n", version)
+		return
+	}
+
+	if *jsonOutput {
+		*format = "json"
+	}
+	if *oneline {
+		*format = "oneline"
+	}
+	if *summary {
+		*format = "summary"
+	}
+	if *metrics {
+		*format = "metrics"
+	}
+
+	// A -format containing {{ or a -format-file is a template
+	var outputTemplate *template.Template
+	if *formatFile != "" || strings.Contains(*format, "{{") {
+		text := *format
+		if *formatFile != "" {
+			data, err := os.ReadFile(*formatFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitUsage)
+			}
+			text = string(data)
+		}
+
+		outputTemplate, err = parseOutputTemplate(text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error in output template: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		*format = "template"
+	}
+
+	if *pretty && *format != "json" && !*quiet {
+		fmt.Fprintln(os.Stderr, "Warning: -pretty only applies to -format=json and has no effect here")
+	}
+
+	// Status bars run sol every few seconds and show any stderr as a failure
+	if *quiet || *format == "oneline" || *format == "waybar" || *check != "" || *alert != "" {
+		diagnostics = io.Discard
+	}
+	weather.Diagnostics = diagnostics
+	if *verboseOutput {
+		verbose = os.Stderr
+	}
+	weather.Verbose = verbose
+
+	if configGiven && !configFound {
+		logf("Warning: config file %s not found, using built-in defaults", configPath)
+	}
+
+	// Print usage information if requested, -config alone does not count
+	settingsGiven := flag.NFlag()
+	if configGiven {
+		settingsGiven--
+	}
+	if settingsGiven == 0 && configFound {
+		debugf("Using location from %s (%.2f, %.2f) and %d days",
+			configPath, cfg.Latitude, cfg.Longitude, cfg.Days)
+	} else if settingsGiven == 0 {
+		logf("Using default location: New York City (%.2f, %.2f) and %d days",
+			cfg.Latitude, cfg.Longitude, cfg.Days)
+		logf("You can specify location and days with: -lat=<value> -lon=<value> -days=<value>")
+		logf("Or look up a place by name with: -city=<name>")
+	}
+
+	// Check whether coordinates or a number of days were given explicitly
+	coordsSet, daysSet, frostSet, intervalSet := false, false, false, false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "frost-threshold" {
+			frostSet = true
+		}
+		if f.Name == "interval" {
+			intervalSet = true
+		}
+		if f.Name == "lat" || f.Name == "lon" {
+			coordsSet = true
+		}
+		if f.Name == "days" || f.Name == "past-days" {
+			daysSet = true
+		}
+	})
+
+	if err := weather.ValidateCoordinates(*latitude, *longitude); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	if *days < 1 {
+		fmt.Fprintln(os.Stderr, "Error: Days must be at least 1")
+		os.Exit(exitUsage)
+	}
+
+	if *days > weather.MaxForecastDays {
+		fmt.Fprintf(os.Stderr, "Error: Days cannot be more than %d\n", weather.MaxForecastDays)
+		os.Exit(exitUsage)
+	}
+
+	if *pastDays < 0 || *pastDays > weather.MaxPastDays {
+		fmt.Fprintf(os.Stderr, "Error: Past days must be between 0 and %d\n", weather.MaxPastDays)
+		os.Exit(exitUsage)
+	}
+
+	if *hours < 0 {
+		fmt.Fprintln(os.Stderr, "Error: Hours cannot be negative")
+		os.Exit(exitUsage)
+	}
+
+	if *listModels {
+		for _, m := range weather.Models {
+			fmt.Printf("%-21s %s\n", m.Name, m.Description)
+		}
+		return
+	}
+
+	if *model != "" && !weather.IsModel(*model) {
+		fmt.Fprintf(os.Stderr, "Error: Unknown model %q, -list-models shows the supported ones\n", *model)
+		os.Exit(exitUsage)
+	}
+
+	if !slices.Contains(localeNames(), *locale) {
+		fmt.Fprintf(os.Stderr, "Error: Unknown locale %q, it must be one of %s\n", *locale, strings.Join(localeNames(), ", "))
+		os.Exit(exitUsage)
+	}
+
+	if *noDaily && *noHourly {
+		fmt.Fprintln(os.Stderr, "Error: -no-daily and -no-hourly together would leave nothing to show")
+		os.Exit(exitUsage)
+	}
+
+	if *hourlyDay != -1 {
+		if *hourlyDay < 0 || *hourlyDay >= weather.MaxForecastDays {
+			fmt.Fprintf(os.Stderr, "Error: -hourly-day must be between 0 and %d, got %d\n", weather.MaxForecastDays-1, *hourlyDay)
+			os.Exit(exitUsage)
+		}
+		if *noHourly || *date != "" {
+			fmt.Fprintln(os.Stderr, "Error: -hourly-day cannot be combined with -no-hourly or -date")
+			os.Exit(exitUsage)
+		}
+	}
+
+	// A server root or a /v1/forecast URL moves every endpoint, any other path only the forecast
+	if *apiURL != "" {
+		if err := validateBaseURL(*apiURL); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid API URL: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		base := strings.TrimSuffix(*apiURL, "/")
+		if u, _ := url.Parse(base); u.Path == "" || strings.HasSuffix(u.Path, "/v1/forecast") {
+			client.SetBaseURL(strings.TrimSuffix(base, "/v1/forecast"))
+		} else {
+			client.BaseURL = *apiURL
+		}
+	}
+
+	// The key is read from the environment here so that -help does not print it
+	if *apiKey == "" {
+		*apiKey = os.Getenv("SOL_API_KEY")
+	}
+	if *apiKey != "" {
+		client.UseAPIKey(*apiKey)
+	}
+
+	if *roundTo < -1 || *roundTo > maxRoundDecimals {
+		fmt.Fprintf(os.Stderr, "Error: -round must be between 0 and %d decimal places, got %d\n", maxRoundDecimals, *roundTo)
+		os.Exit(exitUsage)
+	}
+
+	if *bestWindow != 0 && (*bestWindow%time.Hour != 0 || *bestWindow < time.Hour || *bestWindow > 24*time.Hour) {
+		fmt.Fprintf(os.Stderr, "Error: -best-window must be whole hours from 1h to 24h, got %s\n", *bestWindow)
+		os.Exit(exitUsage)
+	}
+	comfortBand := weather.DefaultComfortBand(*units)
+	if *comfort != "" {
+		comfortBand, err = parseComfortBand(*comfort)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
+
+	// The frost threshold is in the unit of the forecast
+	if !frostSet && *units == "imperial" {
+		*frostThreshold = 32
+	}
+	if *check != "" && *check != "frost" {
+		fmt.Fprintf(os.Stderr, "Error: -check only supports frost, got %q\n", *check)
+		os.Exit(exitUsage)
+	}
+
+	var alertConditions alertExpr
+	if *alert != "" {
+		var err error
+		if alertConditions, err = parseAlert(*alert); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		if *alertDay < 0 || *alertDay >= weather.MaxForecastDays {
+			fmt.Fprintf(os.Stderr, "Error: -alert-day must be between 0 and %d, got %d\n", weather.MaxForecastDays-1, *alertDay)
+			os.Exit(exitUsage)
+		}
+		if *check != "" || *date != "" || *noDaily {
+			fmt.Fprintln(os.Stderr, "Error: -alert cannot be combined with -check, -date or -no-daily")
+			os.Exit(exitUsage)
+		}
+	}
+
+	if *concurrency < 1 || *concurrency > maxConcurrency {
+		fmt.Fprintf(os.Stderr, "Error: -concurrency must be between 1 and %d, got %d\n", maxConcurrency, *concurrency)
+		os.Exit(exitUsage)
+	}
+
+	// -watch=10m from before -interval existed still sets the interval
+	if watch.interval != 0 && !intervalSet {
+		*interval = watch.interval
+	}
+	if watch.on && *interval < time.Minute {
+		fmt.Fprintln(os.Stderr, "Error: Watch interval must be at least 1m")
+		os.Exit(exitUsage)
+	}
+
+	if watch.on && *outputPath != "" {
+		fmt.Fprintln(os.Stderr, "Error: -watch cannot be combined with -output")
+		os.Exit(exitUsage)
+	}
+
+	if *retries < 0 {
+		fmt.Fprintln(os.Stderr, "Error: Retries cannot be negative")
+		os.Exit(exitUsage)
+	}
+	client.Retries = *retries
+
+	if *units != "metric" && *units != "imperial" {
+		fmt.Fprintf(os.Stderr, "Error: Units must be metric or imperial, got %q\n", *units)
+		os.Exit(exitUsage)
+	}
+
+	if *timeFormat != "24h" && *timeFormat != "12h" {
+		fmt.Fprintf(os.Stderr, "Error: Time format must be 24h or 12h, got %q\n", *timeFormat)
+		os.Exit(exitUsage)
+	}
+
+	if *colorMode != "auto" && *colorMode != "always" && *colorMode != "never" {
+		fmt.Fprintf(os.Stderr, "Error: Color must be auto, always or never, got %q\n", *colorMode)
+		os.Exit(exitUsage)
+	}
+
+	if !slices.Contains(formats, *format) {
+		fmt.Fprintf(os.Stderr, "Error: Format must be one of %s or a Go template, got %q\n", strings.Join(formats, ", "), *format)
+		os.Exit(exitUsage)
+	}
+	if *format == "template" && outputTemplate == nil {
+		fmt.Fprintln(os.Stderr, "Error: -format=template needs -format-file")
+		os.Exit(exitUsage)
+	}
+
+	if *csvSection != "hourly" && *csvSection != "daily" {
+		fmt.Fprintf(os.Stderr, "Error: CSV section must be hourly or daily, got %q\n", *csvSection)
+		os.Exit(exitUsage)
+	}
+
+	// -date replaces the days around today with a span of dates, which can lie in the past
+	var span weather.Options
+	spanDays := 0
+	if *endDate != "" && *date == "" {
+		fmt.Fprintln(os.Stderr, "Error: -end-date needs -date")
+		os.Exit(exitUsage)
+	}
+	if *date != "" {
+		if daysSet {
+			fmt.Fprintln(os.Stderr, "Error: -date cannot be combined with -days or -past-days")
+			os.Exit(exitUsage)
+		}
+		if *nowOnly || *format == "oneline" || *format == "summary" || *format == "waybar" || *format == "metrics" {
+			fmt.Fprintln(os.Stderr, "Error: -date cannot be combined with -now, -oneline, -summary, -metrics or waybar output, which show the current conditions")
+			os.Exit(exitUsage)
+		}
+		now := time.Now()
+		span, spanDays, err = dateSpan(*date, *endDate, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		span.Units = *units
+	}
+
+	if *saveConfig {
+		current := config{
+			Latitude:   *latitude,
+			Longitude:  *longitude,
+			Days:       *days,
+			Hours:      *hours,
+			Units:      *units,
+			TimeFormat: *timeFormat,
+
+			PrecipitationThreshold: *precipitationThreshold,
+			WindThreshold:          *windThreshold,
+			WindWarn:               *windWarn,
+		}
+		if err := writeConfig(configPath, current); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailure)
+		}
+		fmt.Printf("Wrote config to %s\n", configPath)
+		return
+	}
+
+	// Saved locations live next to the config file
+	savedPath := savedLocationsPath(configPath)
+	var saved map[string]savedLocation
+	if *listLocations || *deleteLocation != "" || len(locNames) > 0 || *saveLocation != "" {
+		saved, err = loadSavedLocations(savedPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailure)
+		}
+	}
+
+	if *listLocations {
+		if len(saved) == 0 {
+			fmt.Println("No saved locations")
+		}
+		for _, name := range savedLocationNames(saved) {
+			loc := saved[name]
+			if loc.Name != "" {
+				fmt.Printf("%s: %.4f, %.4f (%s)\n", name, loc.Latitude, loc.Longitude, loc.Name)
+			} else {
+				fmt.Printf("%s: %.4f, %.4f\n", name, loc.Latitude, loc.Longitude)
+			}
+		}
+		return
+	}
+
+	if *deleteLocation != "" {
+		if _, err := lookupSavedLocation(saved, *deleteLocation); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		delete(saved, *deleteLocation)
+		if err := writeSavedLocations(savedPath, saved); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailure)
+		}
+		fmt.Printf("Deleted location %q\n", *deleteLocation)
+		return
+	}
+
+	if *citiesFile != "" {
+		names, err := readCitiesFile(*citiesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		cities = append(cities, names...)
+	}
+
+	// Work out which places to show, explicit coordinates win over names
+	var locations []location
+	switch {
+	case *locationList != "":
+		if coordsSet || len(cities) > 0 || len(locNames) > 0 {
+			logf("Warning: -locations given, ignoring -loc, -city and -lat/-lon")
+		}
+
+		parsed, err := parseLocations(*locationList)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		locations = parsed
+	case coordsSet:
+		if len(cities) > 0 || len(locNames) > 0 {
+			logf("Warning: both a place name and -lat/-lon given, using the explicit coordinates")
+		}
+		locations = []location{{Latitude: *latitude, Longitude: *longitude}}
+	case len(cities) > 0 || len(locNames) > 0:
+		for _, names := range locNames {
+			for _, name := range strings.Split(names, ",") {
+				loc, err := lookupSavedLocation(saved, strings.TrimSpace(name))
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(exitUsage)
+				}
+				if loc.Name == "" {
+					loc.Name = strings.TrimSpace(name)
+				}
+				locations = append(locations, location{Name: loc.Name, Latitude: loc.Latitude, Longitude: loc.Longitude})
+			}
+		}
+		for _, city := range cities {
+			locations = append(locations, location{Query: city})
+		}
+	default:
+		locations = []location{{Latitude: *latitude, Longitude: *longitude}}
+	}
+
+	// Rounding happens before anything is requested or cached; places looked up
+	// by name are public already
+	if *roundTo >= 0 {
+		for i := range locations {
+			locations[i].Latitude = roundCoordinate(locations[i].Latitude, *roundTo)
+			locations[i].Longitude = roundCoordinate(locations[i].Longitude, *roundTo)
+		}
+	}
+
+	if *saveLocation != "" && len(locations) > 1 {
+		fmt.Fprintln(os.Stderr, "Error: -save-location needs exactly one location")
+		os.Exit(exitUsage)
+	}
+
+	if *format == "waybar" && len(locations) > 1 {
+		fmt.Fprintln(os.Stderr, "Error: Waybar output needs exactly one location")
+		os.Exit(exitUsage)
+	}
+
+	if !*noCache && *cacheTTL > 0 {
+		dir, err := weather.DefaultCacheDir()
+		if err != nil {
+			logf("Warning: caching disabled: %v", err)
+		} else {
+			client.Cache = &weather.Cache{Dir: dir, TTL: *cacheTTL, MaxStale: max(*maxStale, *cacheTTL)}
+		}
+	}
+
+	// Ctrl-C cancels any request in flight instead of waiting for it
+	client.HTTP.Timeout = *timeout
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// Fetch enough days to cover the hourly rows as well, starting from later today
+	forecastDays := *days
+	lookahead := max(*hours, graphWidth)
+	if *bestWindow > 0 {
+		lookahead = max(lookahead, bestWindowHorizon)
+	}
+	if hourDays := (lookahead+23)/24 + 1; hourDays > forecastDays {
+		forecastDays = min(hourDays, weather.MaxForecastDays)
+	}
+	forecastDays = max(forecastDays, *hourlyDay+1)
+	if *alert != "" {
+		forecastDays = max(forecastDays, *alertDay+1)
+	}
+	fetchOpts := weather.Options{Units: *units, Days: forecastDays, PastDays: *pastDays}
+	reportDays := *pastDays + *days
+	if *alert != "" {
+		reportDays = max(reportDays, *pastDays+*alertDay+1)
+	}
+	if *date != "" {
+		fetchOpts, reportDays = span, spanDays
+	}
+	// The hourly series are still fetched without the hourly section, the daily
+	// details and the current pressure trend are worked out from them
+	if *noDaily {
+		fetchOpts.NoDaily, reportDays = true, 0
+	}
+	fetchOpts.Model = *model
+
+	// -dry-run prints what would be requested and stops before any network access
+	if *dryRun {
+		for _, loc := range locations {
+			if loc.Query != "" {
+				fmt.Println(client.GeocodeURL(loc.Query))
+				fmt.Printf("  (the forecast URL for %q depends on the coordinates this lookup returns)\n", loc.Query)
+				continue
+			}
+			opts := fetchOpts
+			opts.Latitude, opts.Longitude = loc.Latitude, loc.Longitude
+			requestURL, err := client.ForecastURL(opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitUsage)
+			}
+			fmt.Println(requestURL)
+		}
+		return
+	}
+
+	// The forecast goes to -output when given; errors and diagnostics stay on the terminal
+	var out io.Writer = os.Stdout
+	var file *outputFile
+	if *outputPath != "" {
+		file, err = createOutput(*outputPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailure)
+		}
+		out = file
+	}
+	// exit closes the output file first, failing the run when it could not be written
+	exit := func(code int) {
+		if file != nil {
+			if err := file.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				code = exitFailure
+			}
+		}
+		os.Exit(code)
+	}
+
+	// Colors depend on where the forecast ends up, not on -watch's buffer
+	color := useColor(*colorMode, out)
+
+	// show fetches and prints the forecast once to out, returning the exit code
+	show := func(out io.Writer) int {
+		results := fetchForecasts(ctx, client, locations, *concurrency, fetchOpts,
+			fetchExtras{AirQuality: *airQuality, Pollen: *pollen, Marine: *marine, Ensemble: *ensemble})
+
+		// A single location fails the whole run; status bars still get a placeholder
+		if len(results) == 1 && results[0].Err != nil {
+			switch *format {
+			case "oneline":
+				fmt.Fprintln(out, "n/a")
+				fmt.Fprintf(os.Stderr, "Error getting weather forecast: %v\n", results[0].Err)
+				return exitCode(results[0].Err)
+			case "summary":
+				fmt.Fprintln(out, results[0].Location.label()+": n/a")
+				fmt.Fprintf(os.Stderr, "Error getting weather forecast: %v\n", results[0].Err)
+				return exitCode(results[0].Err)
+			case "waybar":
+				encoded, _ := json.Marshal(waybarFailure(results[0].Err))
+				fmt.Fprintln(out, string(encoded))
+				fmt.Fprintf(os.Stderr, "Error getting weather forecast: %v\n", results[0].Err)
+				return exitCode(results[0].Err)
+			case "metrics":
+				// Nothing goes to stdout, so a textfile collector never reads a partial file
+				fmt.Fprintf(os.Stderr, "Error getting weather forecast: %v\n", results[0].Err)
+				return exitCode(results[0].Err)
+			}
+			// Being rate limited is not a fault; say when it is worth trying again
+			var rateErr *weather.RateLimitError
+			if errors.As(results[0].Err, &rateErr) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", rateErr)
+				return exitCode(results[0].Err)
+			}
+			fmt.Fprintf(os.Stderr, "Error getting weather forecast: %v\n", results[0].Err)
+			return exitCode(results[0].Err)
+		}
+
+		// Save the resolved coordinates, which for a city are only known now
+		if *saveLocation != "" {
+			resolved := results[0].Location
+			saved[*saveLocation] = savedLocation{Name: resolved.Name, Latitude: resolved.Latitude, Longitude: resolved.Longitude}
+			if err := writeSavedLocations(savedPath, saved); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitFailure
+			}
+			logf("Saved location %q", *saveLocation)
+		}
+
+		reports := make([]*Report, len(results))
+		// With several locations the first failure decides the exit code
+		code := exitOK
+		for i, result := range results {
+			if result.Err != nil {
+				if code == exitOK {
+					code = exitCode(result.Err)
+				}
+				continue
+			}
+			trendHours := 0
+			if *graph {
+				trendHours = graphWidth
+			}
+			// The single line takes the precipitation chance from the current hour
+			reportHours := *hours
+			if *noHourly {
+				reportHours = 0
+			}
+			if *format == "oneline" || *format == "summary" || *format == "waybar" || *format == "metrics" {
+				reportHours = max(reportHours, 1)
+			}
+			report := buildReport(result.Response, reportDays, reportHours, trendHours, *fromNextHour)
+			report.Location.Name = result.Location.Name
+			report.Model = *model
+			if *hourlyDay >= 0 {
+				report.selectHourlyDay(result.Response, *hourlyDay)
+			}
+			if result.AirQuality != nil {
+				report.AirQuality = newAirQualityEntry(result.AirQuality)
+			}
+			if result.Pollen != nil {
+				report.Pollen = newPollenEntry(result.Pollen)
+			}
+			if result.Ensemble != nil {
+				report.Ensemble = newEnsembleEntry(result.Ensemble, report.Daily, report.Units)
+			}
+			if *marine {
+				// The marine API rejects or returns nulls for points away from the sea
+				if result.MarineErr != nil {
+					var apiErr *weather.APIError
+					if !errors.As(result.MarineErr, &apiErr) {
+						logf("Warning: could not get the marine forecast for %s: %v", result.Location.label(), result.MarineErr)
+					}
+					report.Marine = &MarineEntry{Days: []MarineDay{}}
+				} else {
+					report.Marine = newMarineEntry(result.Marine, report.Daily)
+				}
+			}
+			report.markWindy(*windWarn)
+			report.markRain(*rainThreshold)
+			report.markFrost(*frostThreshold)
+			if *bestWindow > 0 {
+				report.markBestWindow(int(*bestWindow/time.Hour), comfortBand, *units, *daylightOnly)
+			}
+			reports[i] = &report
+		}
+
+		// -check answers with the exit code alone
+		if *check == "frost" {
+			for _, report := range reports {
+				if report != nil && report.frostAhead() {
+					return exitOK
+				}
+			}
+			if code != exitOK {
+				return code
+			}
+			return exitNotForecast
+		}
+
+		// -alert prints a line for each location whose day matches, and nothing otherwise
+		if *alert != "" {
+			matched := false
+			for i, report := range reports {
+				if report == nil {
+					continue
+				}
+				date := report.dayAfter(*alertDay)
+				idx := slices.IndexFunc(report.Daily, func(d DailyEntry) bool { return d.Date == date })
+				if idx < 0 {
+					fmt.Fprintf(os.Stderr, "Error: the forecast for %s has no day %s\n", results[i].Location.label(), date)
+					if code == exitOK {
+						code = exitResponse
+					}
+					continue
+				}
+				day := report.Daily[idx]
+				if conditions := alertConditions.evaluate(day); conditions != nil {
+					fmt.Fprintln(out, alertSentence(results[i].Location.label(), date, day, conditions, report.Units))
+					matched = true
+				}
+			}
+			if matched {
+				return exitAlert
+			}
+			return code
+		}
+
+		opts := renderOptions{
+			NoEmoji:                *noEmoji,
+			Chart:                  *chart,
+			ASCII:                  *ascii,
+			Detail:                 *detail,
+			NoHourly:               *noHourly,
+			Compact:                *compact,
+			Locale:                 *locale,
+			TimeFormat:             *timeFormat,
+			Color:                  color,
+			PrecipitationThreshold: *precipitationThreshold,
+			WindThreshold:          *windThreshold,
+		}
+
+		if *format == "waybar" {
+			encoded, err := json.Marshal(renderWaybar(results[0].Location.label(), *reports[0], opts))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON output: %v\n", err)
+				return exitFailure
+			}
+			fmt.Fprintln(out, string(encoded))
+		} else if *format == "csv" {
+			var labels []string
+			var fetched []Report
+			for i, report := range reports {
+				if report == nil {
+					fmt.Fprintf(os.Stderr, "Error getting weather forecast for %s: %v\n", results[i].Location.label(), results[i].Err)
+					continue
+				}
+				labels = append(labels, results[i].Location.label())
+				fetched = append(fetched, *report)
+			}
+
+			// A reader that stops early, like head, is not an error worth reporting
+			if err := writeCSV(out, *csvSection, labels, fetched); err != nil && !errors.Is(err, syscall.EPIPE) {
+				fmt.Fprintf(os.Stderr, "Error writing CSV output: %v\n", err)
+				return exitFailure
+			}
+		} else if *format == "metrics" {
+			var labels []string
+			var fetched []Report
+			for i, report := range reports {
+				if report == nil {
+					fmt.Fprintf(os.Stderr, "Error getting weather forecast for %s: %v\n", results[i].Location.label(), results[i].Err)
+					continue
+				}
+				labels = append(labels, results[i].Location.label())
+				fetched = append(fetched, *report)
+			}
+
+			if err := writeMetrics(out, labels, fetched); err != nil && !errors.Is(err, syscall.EPIPE) {
+				fmt.Fprintf(os.Stderr, "Error writing metrics output: %v\n", err)
+				return exitFailure
+			}
+		} else if *format == "template" {
+			for i, report := range reports {
+				if report == nil {
+					fmt.Fprintf(os.Stderr, "Error getting weather forecast for %s: %v\n", results[i].Location.label(), results[i].Err)
+					continue
+				}
+
+				output, err := renderTemplate(outputTemplate, *report, opts)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error in output template: %v\n", err)
+					return exitFailure
+				}
+				fmt.Fprint(out, output)
+			}
+		} else if *format == "oneline" {
+			for i, report := range reports {
+				prefix := ""
+				if len(results) > 1 {
+					prefix = results[i].Location.label() + ": "
+				}
+
+				if report == nil {
+					fmt.Fprintln(out, prefix+"n/a")
+					fmt.Fprintf(os.Stderr, "Error getting weather forecast for %s: %v\n", results[i].Location.label(), results[i].Err)
+					continue
+				}
+				fmt.Fprintln(out, prefix+renderOneline(*report, opts))
+			}
+		} else if *format == "summary" {
+			for i, report := range reports {
+				if report == nil {
+					fmt.Fprintln(out, results[i].Location.label()+": n/a")
+					fmt.Fprintf(os.Stderr, "Error getting weather forecast for %s: %v\n", results[i].Location.label(), results[i].Err)
+					continue
+				}
+				fmt.Fprintln(out, renderSummary(results[i].Location.label(), *report, opts))
+			}
+		} else if *format == "json" {
+			outputs := []interface{}{}
+			for i, report := range reports {
+				// Failures are reported in place so the array keeps the input order
+				if report == nil {
+					loc := results[i].Location
+					name := loc.Name
+					if name == "" {
+						name = loc.Query
+					}
+					outputs = append(outputs, FailedReport{
+						Location: ReportLocation{Name: name, Latitude: loc.Latitude, Longitude: loc.Longitude},
+						Error:    results[i].Err.Error(),
+					})
+					continue
+				}
+
+				// The current conditions on their own, e.g. for status bars
+				if *nowOnly {
+					outputs = append(outputs, report.Current)
+				} else {
+					outputs = append(outputs, report)
+				}
+			}
+
+			// Several locations are written as an array
+			var output interface{} = outputs
+			if len(results) == 1 {
+				output = outputs[0]
+			}
+
+			var encoded []byte
+			var err error
+			if *pretty {
+				encoded, err = json.MarshalIndent(output, "", "  ")
+			} else {
+				encoded, err = json.Marshal(output)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding JSON output: %v\n", err)
+				return exitFailure
+			}
+			fmt.Fprintln(out, string(encoded))
+		} else {
+			for i, report := range reports {
+				if len(results) > 1 {
+					if i > 0 {
+						fmt.Fprintln(out)
+					}
+					fmt.Fprintf(out, "===== %s =====\n", results[i].Location.label())
+				}
+
+				if report == nil {
+					fmt.Fprintf(out, "Error getting weather forecast: %v\n", results[i].Err)
+					continue
+				}
+
+				if *nowOnly {
+					renderNow(out, *report, opts)
+				} else {
+					renderText(out, *report, opts)
+				}
+			}
+		}
+
+		// With several locations the failures are summed up at the end, whatever the format
+		var failed []string
+		for _, result := range results {
+			if result.Err != nil {
+				failed = append(failed, result.Location.label())
+			}
+		}
+		if len(results) > 1 && len(failed) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: %d of %d locations could not be fetched: %s\n",
+				len(failed), len(results), strings.Join(failed, "; "))
+		}
+
+		return code
+	}
+
+	if !watch.on {
+		exit(show(out))
+	}
+
+	// Refresh until interrupted; the cache keeps intervals shorter than -cache-ttl off the API.
+	// Each forecast is rendered to a buffer first, so a failed refresh keeps the last good one.
+	var last []byte
+	var updated time.Time
+	for {
+		clearScreen(out)
+		var next bytes.Buffer
+		code := show(&next)
+		if ctx.Err() != nil {
+			exit(exitOK)
+		}
+		switch {
+		case code == exitOK:
+			last, updated = next.Bytes(), time.Now()
+			out.Write(last)
+		case updated.IsZero():
+			// Nothing better to show yet
+			out.Write(next.Bytes())
+		default:
+			logf("Warning: the refresh at %s failed, showing the forecast from %s",
+				time.Now().Format("15:04"), updated.Format("15:04"))
+			out.Write(last)
+		}
+
+		if updated.IsZero() {
+			logf("Refreshing every %s, press Ctrl-C to stop", *interval)
+		} else {
+			logf("Last updated %s, refreshing every %s, press Ctrl-C to stop", updated.Format("15:04:05"), *interval)
+		}
+		if !waitCountdown(ctx, diagnostics, *interval) {
+			exit(exitOK)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// metric is one gauge of the -metrics output
+type metric struct {
+	name string
+	help string
+	// value returns the sample for a report, false when it has none
+	value func(Report) (float64, bool)
+}
+
+// unitSuffixes turn the API's units into Prometheus base unit names
+var unitSuffixes = map[string]string{
+	"°C":   "celsius",
+	"°F":   "fahrenheit",
+	"km/h": "kilometers_per_hour",
+	"mph":  "miles_per_hour",
+}
+
+// unitName appends the Prometheus name of unit to base, leaving base bare for
+// units it does not know rather than ending it with an underscore
+func unitName(base, unit string) string {
+	if suffix, ok := unitSuffixes[unit]; ok {
+		return base + "_" + suffix
+	}
+	return base
+}
+
+// labelEscaper escapes label values as the text exposition format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// currentMetrics lists the gauges for the current conditions, named after the units of report
+func currentMetrics(units ReportUnits) []metric {
+	return []metric{
+		{
+			name: unitName("sol_temperature", units.Temperature),
+			help: "Current air temperature at 2 m.",
+			value: func(r Report) (float64, bool) {
+				return r.Current.Temperature, true
+			},
+		},
+		{
+			name: "sol_precipitation_probability_percent",
+			help: "Chance of precipitation in the current hour.",
+			value: func(r Report) (float64, bool) {
+				if len(r.Hourly) == 0 || r.Hourly[0].PrecipitationProbability == nil {
+					return 0, false
+				}
+				return *r.Hourly[0].PrecipitationProbability, true
+			},
+		},
+		{
+			name: unitName("sol_wind_speed", units.WindSpeed),
+			help: "Current wind speed at 10 m.",
+			value: func(r Report) (float64, bool) {
+				return r.Current.WindSpeed, true
+			},
+		},
+	}
+}
+
+// writeMetrics writes the current conditions in the Prometheus text exposition
+// format, one sample per location, for the node_exporter textfile collector
+func writeMetrics(w io.Writer, labels []string, reports []Report) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	for _, m := range currentMetrics(reports[0].Units) {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name); err != nil {
+			return err
+		}
+		for i, report := range reports {
+			value, ok := m.value(report)
+			if !ok {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%s{location=\"%s\"} %g\n", m.name, labelEscaper.Replace(labels[i]), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
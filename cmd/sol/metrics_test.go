@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteMetricsNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		units ReportUnits
+		want  []string
+	}{
+		{"metric", ReportUnits{Temperature: "°C", WindSpeed: "km/h"}, []string{"sol_temperature_celsius", "sol_wind_speed_kilometers_per_hour"}},
+		{"imperial", ReportUnits{Temperature: "°F", WindSpeed: "mph"}, []string{"sol_temperature_fahrenheit", "sol_wind_speed_miles_per_hour"}},
+		{"unknown units", ReportUnits{Temperature: "K", WindSpeed: "kn"}, []string{"sol_temperature", "sol_wind_speed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Report{Units: tt.units}
+			report.Current.Temperature = 21.5
+			report.Current.WindSpeed = 12
+			var out strings.Builder
+			if err := writeMetrics(&out, []string{"Berlin"}, []Report{report}); err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range tt.want {
+				if !strings.Contains(out.String(), "# TYPE "+name+" gauge\n") {
+					t.Errorf("no gauge %s in\n%s", name, out.String())
+				}
+			}
+			if strings.Contains(out.String(), "_{") || strings.Contains(out.String(), "_ ") {
+				t.Errorf("metric name with a trailing underscore in\n%s", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// outputFile is the file given with -output. Writes are buffered, so errors
+// are reported by Close.
+type outputFile struct {
+	*bufio.Writer
+	file *os.File
+}
+
+// createOutput creates or truncates the file at path
+func createOutput(path string) (*outputFile, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating output file: %w", err)
+	}
+	return &outputFile{Writer: bufio.NewWriter(file), file: file}, nil
+}
+
+// Close flushes the buffered output and closes the file
+func (o *outputFile) Close() error {
+	if err := o.Flush(); err != nil {
+		o.file.Close()
+		return fmt.Errorf("error writing output file: %w", err)
+	}
+	if err := o.file.Close(); err != nil {
+		return fmt.Errorf("error writing output file: %w", err)
+	}
+	return nil
+}
+
+// watchFlag is -watch, a switch that also takes the refresh interval itself
+// as it did before -interval existed, e.g. -watch=10m
+type watchFlag struct {
+	on bool
+	// interval is set when -watch was given a duration
+	interval time.Duration
+}
+
+func (w *watchFlag) String() string {
+	if w == nil || !w.on {
+		return "false"
+	}
+	if w.interval != 0 {
+		return w.interval.String()
+	}
+	return "true"
+}
+
+func (w *watchFlag) Set(value string) error {
+	if on, err := strconv.ParseBool(value); err == nil {
+		w.on, w.interval = on, 0
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("must be true, false or an interval like 10m")
+	}
+	w.on, w.interval = d != 0, d
+	return nil
+}
+
+// IsBoolFlag lets -watch be given without a value
+func (w *watchFlag) IsBoolFlag() bool {
+	return true
+}
+
+// waitCountdown waits for d, counting down on w when it is a terminal, and
+// reports whether the wait ran out rather than ctx being cancelled
+func waitCountdown(ctx context.Context, w io.Writer, d time.Duration) bool {
+	deadline := time.Now().Add(d)
+	f, ok := w.(*os.File)
+	if !ok || !isTerminal(f) {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(d):
+			return true
+		}
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		left := time.Until(deadline).Round(time.Second)
+		if left <= 0 {
+			fmt.Fprint(f, "\r\x1b[K")
+			return true
+		}
+		fmt.Fprintf(f, "\rNext refresh in %s\x1b[K", left)
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(f)
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// clearScreen clears a terminal before -watch redraws the forecast; files and pipes are left alone
+func clearScreen(w io.Writer) {
+	if f, ok := w.(*os.File); ok && isTerminal(f) {
+		fmt.Fprint(f, "\x1b[H\x1b[2J")
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import "fmt"
+
+// rainLookahead is how many hours from the current one the rain outlook covers
+const rainLookahead = 12
+
+// rainMergeGap is the shortest dry spell that ends a spell of rain; shorter
+// breaks between showers are counted as part of it
+const rainMergeGap = 2
+
+// RainOutlook is the next spell of precipitation within the coming hours
+type RainOutlook struct {
+	// Hours is how many hours ahead were looked at
+	Hours int `json:"hours"`
+	// Now is set when it is already raining in the current hour
+	Now bool `json:"now"`
+	// Start is the first wet hour, empty when no rain is expected. End is the
+	// first dry hour after the spell, empty when it lasts past the outlook.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+	// Total is the precipitation of the whole spell
+	Total float64 `json:"total"`
+}
+
+// wetHour reports whether an hour has precipitation with a probability of at least
+// threshold percent; hours without a probability go by the amount alone
+func wetHour(hour HourlyEntry, threshold float64) bool {
+	if hour.Precipitation == nil || *hour.Precipitation <= 0 {
+		return false
+	}
+	return hour.PrecipitationProbability == nil || *hour.PrecipitationProbability >= threshold
+}
+
+// findRain looks for the first spell of wet hours in hours, which start at the
+// current hour, merging showers separated by less than rainMergeGap dry hours
+func findRain(hours []HourlyEntry, threshold float64) RainOutlook {
+	outlook := RainOutlook{Hours: len(hours)}
+	start, last := -1, -1
+	for i, hour := range hours {
+		if !wetHour(hour, threshold) {
+			if start >= 0 && i-last >= rainMergeGap {
+				break
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		last = i
+	}
+	if start < 0 {
+		return outlook
+	}
+
+	outlook.Now = start == 0 && hours[0].Now
+	outlook.Start = hours[start].Time
+	if last+1 < len(hours) {
+		outlook.End = hours[last+1].Time
+	}
+	for _, hour := range hours[start : last+1] {
+		outlook.Total += *hour.Precipitation
+	}
+	return outlook
+}
+
+// markRain works out the rain outlook for the hours after the current one
+func (r *Report) markRain(threshold float64) {
+	if len(r.upcoming) == 0 {
+		return
+	}
+	outlook := findRain(r.upcoming[:min(rainLookahead, len(r.upcoming))], threshold)
+	r.Rain = &outlook
+}
+
+// rainSentence describes the outlook like "Rain starting around 15:00, ending around 19:00 (7.0 mm total)"
+func (opts renderOptions) rainSentence(rain RainOutlook, unit string) string {
+	total := fmt.Sprintf(opts.tr("(%.1f %s total)"), rain.Total, unit)
+	switch {
+	case rain.Start == "":
+		return fmt.Sprintf(opts.tr("No rain expected in the next %d hours"), rain.Hours)
+	case rain.Now && rain.End == "":
+		return fmt.Sprintf(opts.tr("Raining now and for at least the next %d hours %s"), rain.Hours, total)
+	case rain.Now:
+		return fmt.Sprintf(opts.tr("Raining now, ending around %s %s"), opts.clock(rain.End), total)
+	case rain.End == "":
+		return fmt.Sprintf(opts.tr("Rain starting around %s and lasting beyond the next %d hours %s"),
+			opts.clock(rain.Start), rain.Hours, total)
+	default:
+		return fmt.Sprintf(opts.tr("Rain starting around %s, ending around %s %s"),
+			opts.clock(rain.Start), opts.clock(rain.End), total)
+	}
+}
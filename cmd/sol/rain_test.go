@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// rainHours returns hours from 00:00 with the precipitation amounts and, when
+// given, probabilities; NaN stands for a missing probability. The first hour is now.
+func rainHours(precipitation []float64, probabilities []float64) []HourlyEntry {
+	hours := make([]HourlyEntry, len(precipitation))
+	for i := range hours {
+		hours[i].Time = fmt.Sprintf("2026-06-01T%02d:00", i)
+		hours[i].Precipitation = &precipitation[i]
+		if i < len(probabilities) && !math.IsNaN(probabilities[i]) {
+			hours[i].PrecipitationProbability = &probabilities[i]
+		}
+	}
+	hours[0].Now = true
+	return hours
+}
+
+func TestFindRain(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name          string
+		precipitation []float64
+		probabilities []float64
+		want          RainOutlook
+	}{
+		{"dry", []float64{0, 0, 0}, nil, RainOutlook{Hours: 3}},
+		{"one shower", []float64{0, 1, 2, 0, 0}, nil, RainOutlook{Hours: 5, Start: "2026-06-01T01:00", End: "2026-06-01T03:00", Total: 3}},
+		{"raining now", []float64{0.5, 0, 0}, nil, RainOutlook{Hours: 3, Now: true, Start: "2026-06-01T00:00", End: "2026-06-01T01:00", Total: 0.5}},
+		{"one-hour gap is merged", []float64{1, 0, 2, 0, 0}, nil, RainOutlook{Hours: 5, Now: true, Start: "2026-06-01T00:00", End: "2026-06-01T03:00", Total: 3}},
+		{"several one-hour gaps", []float64{0, 1, 0, 1, 0, 1, 0, 0, 4}, nil, RainOutlook{Hours: 9, Start: "2026-06-01T01:00", End: "2026-06-01T06:00", Total: 3}},
+		{"two-hour gap ends the spell", []float64{1, 0, 0, 2}, nil, RainOutlook{Hours: 4, Now: true, Start: "2026-06-01T00:00", End: "2026-06-01T01:00", Total: 1}},
+		{"gap at the end", []float64{0, 1, 0}, nil, RainOutlook{Hours: 3, Start: "2026-06-01T01:00", End: "2026-06-01T02:00", Total: 1}},
+		{"lasting past the outlook", []float64{0, 0, 1, 1}, nil, RainOutlook{Hours: 4, Start: "2026-06-01T02:00", Total: 2}},
+		{"unlikely rain is dry", []float64{1, 1, 1, 0}, []float64{20, 49, 50, 90}, RainOutlook{Hours: 4, Start: "2026-06-01T02:00", End: "2026-06-01T03:00", Total: 1}},
+		{"missing probability", []float64{0, 2, 0}, []float64{0, nan, 0}, RainOutlook{Hours: 3, Start: "2026-06-01T01:00", End: "2026-06-01T02:00", Total: 2}},
+		{"likely but no amount", []float64{0, 0}, []float64{90, 90}, RainOutlook{Hours: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findRain(rainHours(tt.precipitation, tt.probabilities), 50)
+			if got != tt.want {
+				t.Errorf("findRain(%v, %v) = %+v, want %+v", tt.precipitation, tt.probabilities, got, tt.want)
+			}
+		})
+	}
+}
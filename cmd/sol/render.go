@@ -0,0 +1,576 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// renderOptions controls how the text output looks
+type renderOptions struct {
+	NoEmoji bool
+	// Chart adds a temperature sparkline above the hourly rows
+	Chart bool
+	// ASCII draws sparklines with plain ASCII characters
+	ASCII bool
+	// Detail adds the dew point, wind, daytime UV index, cloud layers and surface pressure to the hourly rows
+	Detail bool
+	// NoHourly leaves out the hourly section, header included
+	NoHourly bool
+	// Compact prints each day on a single line
+	Compact bool
+	// Locale picks the language of the labels, see locales; "" and "en" are English
+	Locale string
+	// TimeFormat is "24h" or "12h"
+	TimeFormat string
+	// Color turns on ANSI colors for temperatures, likely precipitation and strong wind
+	Color bool
+	// PrecipitationThreshold is the probability in percent from which precipitation is highlighted
+	PrecipitationThreshold float64
+	// WindThreshold is the speed, in the output units, above which wind is highlighted
+	WindThreshold float64
+}
+
+// condition describes the weather like "Partly cloudy ⛅", leaving out the icon when emoji are off.
+// When snow makes up most of the precipitation, rain and drizzle get the snow icon instead.
+func (opts renderOptions) condition(code *weather.WeatherCode, snowy bool) string {
+	if code == nil {
+		return "n/a"
+	}
+	if opts.NoEmoji {
+		return code.String()
+	}
+	icon := code.Emoji()
+	if category := code.Category(); snowy && (category == "rain" || category == "drizzle") {
+		icon = snowEmoji
+	}
+	return code.String() + " " + icon
+}
+
+const snowEmoji = "🌨️"
+
+// stargazingCloudCover is the mean night cloud cover in percent below which a night counts as clear
+const stargazingCloudCover = 25
+
+// gustFactor is how much stronger than the sustained wind gusts must be to be shown
+const gustFactor = 1.3
+
+// gusts describes gusts like " (gusts 45.0 km/h)" when they are notably stronger than the wind
+func (opts renderOptions) gusts(speed, gusts *float64, unit string) string {
+	if speed == nil || gusts == nil || *gusts <= *speed*gustFactor {
+		return ""
+	}
+	return fmt.Sprintf(" (%s %.1f %s)", opts.tr("gusts"), *gusts, unit)
+}
+
+// formatValue prints a value with one decimal, or "n/a" when it is missing
+func formatValue(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f", *v)
+}
+
+// clock turns a local ISO time like "2024-06-03T05:25" into "05:25" or "5:25 AM"
+func (opts renderOptions) clock(iso string) string {
+	t, err := time.Parse("2006-01-02T15:04", iso)
+	if err != nil {
+		return iso
+	}
+	if opts.TimeFormat == "12h" {
+		return t.Format("3:04 PM")
+	}
+	return t.Format("15:04")
+}
+
+// formatDaylight prints a number of seconds as "13h16m"
+func formatDaylight(seconds float64) string {
+	d := time.Duration(seconds) * time.Second
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// feelsDifferent reports whether the apparent temperature is worth showing,
+// i.e. it is known and differs from the air temperature by more than a degree
+func feelsDifferent(actual, apparent *float64) bool {
+	return actual != nil && apparent != nil && math.Abs(*apparent-*actual) > 1
+}
+
+// feelsLike returns "feels like 12.3°C", or "" when it adds nothing
+func (opts renderOptions) feelsLike(actual, apparent *float64, unit string) string {
+	if !feelsDifferent(actual, apparent) {
+		return ""
+	}
+	return opts.tr("feels like") + " " + formatValue(apparent) + unit
+}
+
+// dailyProbability describes the chance of precipitation as "(probability: max 80%, mean 35%)",
+// leaving out the mean when the API has none
+func (opts renderOptions) dailyProbability(day DailyEntry) string {
+	if day.PrecipitationProbabilityMean == nil {
+		return fmt.Sprintf("(%s: %s%%)", opts.tr("probability"), formatValue(day.PrecipitationProbabilityMax))
+	}
+	return fmt.Sprintf("(%s: %s %s%%, %s %s%%)", opts.tr("probability"),
+		opts.tr("max"), formatValue(day.PrecipitationProbabilityMax), opts.tr("mean"), formatValue(day.PrecipitationProbabilityMean))
+}
+
+// dayLabel names a day relative to today, the date of the current conditions:
+// "Yesterday", "3 days ago", "Today", "Tomorrow", and the weekday like "Wednesday"
+// after that, or the date itself when it cannot be parsed. Without a usable
+// current time the position i in the list is used, with 0 as today.
+func (opts renderOptions) dayLabel(date, currentTime string, i int) string {
+	offset := i
+	day, err := time.Parse("2006-01-02", date)
+	today, todayErr := time.Parse("2006-01-02", strings.SplitN(currentTime, "T", 2)[0])
+	if err == nil && todayErr == nil {
+		offset = int(math.Round(day.Sub(today).Hours() / 24))
+	}
+
+	switch {
+	case offset == -1:
+		return opts.tr("Yesterday")
+	case offset < 0:
+		return fmt.Sprintf(opts.tr("%d days ago"), -offset)
+	case offset == 0:
+		return opts.tr("Today")
+	case offset == 1:
+		return opts.tr("Tomorrow")
+	case err != nil:
+		return date
+	default:
+		return opts.tr(day.Weekday().String())
+	}
+}
+
+// formatAge prints a duration as "42m" or "3h05m"
+func formatAge(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// renderNow writes the single "Now:" line with the current conditions, which
+// archive reports do not have
+func renderNow(w io.Writer, report Report, opts renderOptions) {
+	current := report.Current
+	if current.Time != "" {
+		feels := opts.feelsLike(&current.Temperature, &current.ApparentTemperature, report.Units.Temperature)
+		if feels != "" {
+			feels = " (" + feels + ")"
+		}
+		fmt.Fprintf(w, "%s: %s, %s%s, %s %.0f%%, %s %s %s %s%s\n", opts.tr("Now"),
+			opts.condition(&current.WeatherCode, false),
+			opts.temperature(&current.Temperature, report.Units.Temperature, 0), feels,
+			opts.tr("humidity"), current.RelativeHumidity, opts.tr("wind"),
+			opts.windSpeed(&current.WindSpeed, fmt.Sprintf("%.1f %s", current.WindSpeed, report.Units.WindSpeed)),
+			opts.tr("from"), weather.DegreesToCompass(current.WindDirection), pressure(current))
+	}
+	if report.Rain != nil {
+		fmt.Fprintln(w, opts.rainSentence(*report.Rain, report.Units.Precipitation))
+	}
+	if report.bestWindowHours > 0 {
+		fmt.Fprintln(w, opts.windowSentence(report))
+	}
+
+	if aq := report.AirQuality; aq != nil {
+		var indexes []string
+		if aq.USAQI != nil {
+			indexes = append(indexes, fmt.Sprintf("%s (%s %.0f)", opts.tr(aq.USCategory), opts.tr("US AQI"), *aq.USAQI))
+		}
+		if aq.EuropeanAQI != nil {
+			indexes = append(indexes, fmt.Sprintf("%s (%s %.0f)", opts.tr(aq.Category), opts.tr("European AQI"), *aq.EuropeanAQI))
+		}
+		if len(indexes) == 0 {
+			indexes = append(indexes, "n/a")
+		}
+		fmt.Fprintf(w, "%s: %s, PM2.5 %s µg/m³, PM10 %s µg/m³, %s %s µg/m³\n", opts.tr("Air quality"), strings.Join(indexes, ", "),
+			formatValue(aq.PM25), formatValue(aq.PM10), opts.tr("ozone"), formatValue(aq.Ozone))
+		if aq.WorstUSAQI != nil {
+			fmt.Fprintf(w, "  %s: %s, %s %.0f (%s)\n", opts.tr("Worst hour today"), opts.clock(aq.WorstHour),
+				opts.tr("US AQI"), *aq.WorstUSAQI, opts.tr(weather.USAQICategory(*aq.WorstUSAQI)))
+		}
+	}
+
+	if pollen := report.Pollen; pollen != nil {
+		fmt.Fprintf(w, "%s: %s\n", opts.tr("Pollen"), opts.pollenSummary(*pollen))
+	}
+	if marine := report.Marine; marine != nil && !marine.Available {
+		fmt.Fprintln(w, opts.tr("Sea state: inland, no marine forecast for this location"))
+	}
+}
+
+// seaState describes one day of the marine forecast, like
+// "waves up to 1.8 m from WNW ↘, period 7.5 s, swell 1.2 m"
+func (opts renderOptions) seaState(day MarineDay, unit string) string {
+	state := opts.tr("waves up to") + " " + formatValue(day.WaveHeightMax) + " " + unit
+	if day.WaveDirection != nil {
+		state += " " + opts.tr("from") + " " + weather.DegreesToCompass(*day.WaveDirection)
+	}
+	if day.WavePeriodMax != nil {
+		state += fmt.Sprintf(", %s %.1f s", opts.tr("period"), *day.WavePeriodMax)
+	}
+	if day.SwellHeightMax != nil {
+		state += fmt.Sprintf(", %s %.1f %s", opts.tr("swell"), *day.SwellHeightMax, unit)
+	}
+	return state
+}
+
+// pollenSummary lists the species above Low, like "birch High (120 grains/m³)"
+func (opts renderOptions) pollenSummary(pollen PollenEntry) string {
+	if !pollen.Available {
+		return opts.tr("pollen data not available for this location")
+	}
+	var parts []string
+	for _, reading := range pollen.Species {
+		if reading.Level != "Low" {
+			parts = append(parts, fmt.Sprintf("%s %s (%.0f %s)", reading.Species, opts.tr(reading.Level), reading.Grains, opts.tr("grains/m³")))
+		}
+	}
+	if len(parts) == 0 {
+		return opts.tr("Low")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pressure describes the current pressure like ", 1013 hPa ↑ rising", or "" when it is unknown
+func pressure(current CurrentEntry) string {
+	if current.Pressure == 0 {
+		return ""
+	}
+	text := fmt.Sprintf(", %.0f hPa", current.Pressure)
+	if current.PressureTrend != "" {
+		text += " " + current.PressureTrend
+	}
+	return text
+}
+
+// formatDayCompact puts a day on one line for -compact, like
+// "Mon 2024-06-03  12–21°C  ☔2.1mm(40%)  💨18km/h"
+func formatDayCompact(day DailyEntry, units ReportUnits, opts renderOptions) string {
+	weekday := "   "
+	if t, err := time.Parse("2006-01-02", day.Date); err == nil {
+		// The first three letters of the weekday, in the -locale language, padded when it is shorter
+		name := []rune(opts.tr(t.Weekday().String()))
+		weekday = fmt.Sprintf("%-3s", string(name[:min(3, len(name))]))
+	}
+	rain, wind := "☔", "💨"
+	if opts.NoEmoji {
+		rain, wind = opts.tr("precip")+" ", opts.tr("wind")+" "
+	}
+
+	temperatures := fmt.Sprintf("%s–%s%s", roundValue(0, day.TemperatureMin), roundValue(0, day.TemperatureMax), units.Temperature)
+	precipitation := fmt.Sprintf("%s%s%s(%s%%)", rain, formatValue(day.PrecipitationSum), units.Precipitation,
+		roundValue(0, day.PrecipitationProbabilityMax))
+	return fmt.Sprintf("%s %s  %-9s  %s  %s",
+		weekday, day.Date, temperatures,
+		opts.precipitationProbability(day.PrecipitationProbabilityMax, fmt.Sprintf("%-16s", precipitation)),
+		opts.windSpeed(day.WindSpeedMax, wind+roundValue(0, day.WindSpeedMax)+units.WindSpeed))
+}
+
+// renderSummary returns the current conditions as one plain sentence like
+// "New York: 18°C, slight rain, 40% precip, wind 12 km/h NE"
+func renderSummary(label string, report Report, opts renderOptions) string {
+	current := report.Current
+	parts := []string{
+		opts.paint(temperatureColor(current.Temperature, report.Units.Temperature),
+			fmt.Sprintf("%.0f%s", current.Temperature, report.Units.Temperature)),
+		strings.ToLower(current.WeatherCode.String()),
+	}
+	if len(report.Hourly) > 0 && report.Hourly[0].PrecipitationProbability != nil {
+		probability := report.Hourly[0].PrecipitationProbability
+		parts = append(parts, opts.precipitationProbability(probability, fmt.Sprintf("%.0f%% precip", *probability)))
+	}
+	parts = append(parts, "wind "+opts.windSpeed(&current.WindSpeed, fmt.Sprintf("%.0f %s", current.WindSpeed, report.Units.WindSpeed))+
+		" "+weather.CompassPoint(current.WindDirection))
+	return label + ": " + strings.Join(parts, ", ")
+}
+
+// renderOneline returns the current conditions as one short line like "☁️ 21°C ↓3% 💨12km/h"
+func renderOneline(report Report, opts renderOptions) string {
+	current := report.Current
+	condition := current.WeatherCode.Emoji()
+	if opts.NoEmoji {
+		condition = current.WeatherCode.String()
+	}
+	temperature := opts.paint(temperatureColor(current.Temperature, report.Units.Temperature),
+		fmt.Sprintf("%.0f%s", current.Temperature, report.Units.Temperature))
+	parts := []string{condition, temperature}
+
+	if len(report.Hourly) > 0 && report.Hourly[0].PrecipitationProbability != nil {
+		probability := report.Hourly[0].PrecipitationProbability
+		marker := "↓"
+		if opts.NoEmoji {
+			marker = "rain "
+		}
+		parts = append(parts, opts.precipitationProbability(probability, fmt.Sprintf("%s%.0f%%", marker, *probability)))
+	}
+
+	marker := "💨"
+	if opts.NoEmoji {
+		marker = "wind "
+	}
+	parts = append(parts, opts.windSpeed(&current.WindSpeed, fmt.Sprintf("%s%.0f%s", marker, current.WindSpeed, report.Units.WindSpeed)))
+	return strings.Join(parts, " ")
+}
+
+// renderSun writes the sunrise and sunset line for a day
+func renderSun(w io.Writer, day DailyEntry, opts renderOptions) {
+	daylight := ""
+	if day.DaylightDuration != nil {
+		daylight = fmt.Sprintf(" (%s %s)", formatDaylight(*day.DaylightDuration), opts.tr("daylight"))
+	}
+
+	// Polar day or night leaves one or both times empty
+	if day.Sunrise == "" || day.Sunset == "" {
+		if day.DaylightDuration != nil && *day.DaylightDuration > 0 {
+			fmt.Fprintf(w, "  %s%s\n", opts.tr("No sunrise or sunset (polar day)"), daylight)
+		} else {
+			fmt.Fprintf(w, "  %s\n", opts.tr("No sunrise or sunset (polar night)"))
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "  %s %s, %s %s%s\n", opts.tr("Sunrise"), opts.clock(day.Sunrise), opts.tr("Sunset"), opts.clock(day.Sunset), daylight)
+}
+
+// renderText writes the human readable forecast
+func renderText(w io.Writer, report Report, opts renderOptions) {
+	extra := ""
+	if report.Model != "" {
+		extra = " - Model: " + report.Model
+	}
+	if report.CacheAgeSeconds > 0 {
+		extra += fmt.Sprintf(" (cached, %s old)", formatAge(time.Duration(report.CacheAgeSeconds)*time.Second))
+	}
+
+	if report.Location.Name != "" {
+		fmt.Fprintf(w, "%s: %s - %s: %s%s\n", opts.tr("Weather for"), report.Location.Name, opts.tr("Timezone"), report.Timezone, extra)
+	} else {
+		fmt.Fprintf(w, "%s: %.4f, %.4f - %s: %s%s\n", opts.tr("Weather for"), report.Location.Latitude, report.Location.Longitude,
+			opts.tr("Timezone"), report.Timezone, extra)
+	}
+	// Series the model does not cover are shown as n/a
+	if len(report.Unavailable) > 0 {
+		source := opts.tr("the forecast")
+		if report.Model != "" {
+			source = report.Model
+		}
+		fmt.Fprintf(w, opts.tr("Not available from %s here: %s")+"\n", source, strings.Join(report.Unavailable, ", "))
+	}
+	if len(report.Incomplete) > 0 {
+		fmt.Fprintf(w, opts.tr("Incomplete in the API response, shown as n/a: %s")+"\n", strings.Join(report.Incomplete, ", "))
+	}
+	if report.Ensemble != nil {
+		fmt.Fprintf(w, opts.tr("Ensemble ranges from %s, on a coarser grid than the forecast")+"\n", report.Ensemble.Model)
+	}
+
+	renderNow(w, report, opts)
+	fmt.Fprintln(w)
+
+	units := report.Units
+	for i, day := range report.Daily {
+		if opts.Compact {
+			fmt.Fprintln(w, formatDayCompact(day, units, opts))
+			continue
+		}
+		label := opts.dayLabel(day.Date, report.Current.Time, i)
+		if report.Current.Time == "" {
+			// Archive days have no today to count from, so they go by weekday
+			if t, err := time.Parse("2006-01-02", day.Date); err == nil {
+				label = opts.tr(t.Weekday().String())
+			}
+		}
+		fmt.Fprintf(w, "%s (%s): %s\n", label, day.Date,
+			opts.condition(day.WeatherCode, snowDominant(day.SnowfallSum, day.PrecipitationSum, units)))
+		feels := ""
+		if feelsDifferent(day.TemperatureMin, day.ApparentTemperatureMin) || feelsDifferent(day.TemperatureMax, day.ApparentTemperatureMax) {
+			feels = fmt.Sprintf(" (%s %s%s %s %s%s)", opts.tr("feels like"),
+				formatValue(day.ApparentTemperatureMin), units.Temperature, opts.tr("to"),
+				formatValue(day.ApparentTemperatureMax), units.Temperature)
+		}
+		fmt.Fprintf(w, "  %s: %s %s %s%s\n", opts.tr("Temperature"),
+			opts.temperature(day.TemperatureMin, units.Temperature, 0), opts.tr("to"),
+			opts.temperature(day.TemperatureMax, units.Temperature, 0), feels)
+		if day.Frost {
+			fmt.Fprintln(w, opts.paint(ansiHighlight, fmt.Sprintf("  "+opts.tr("FROST WARNING: low of %s%s"), formatValue(day.TemperatureMin), units.Temperature)))
+		}
+		if report.Ensemble != nil {
+			if spread, ok := report.Ensemble.ensembleDay(day.Date); ok {
+				fmt.Fprintf(w, "  "+opts.tr("High: %s%s (%.0f–%.0f%s across %d members), precipitation in %.0f%% of members")+"\n",
+					roundValue(0, day.TemperatureMax), units.Temperature, spread.TemperatureMaxLow, spread.TemperatureMaxHigh,
+					units.Temperature, spread.Members, spread.PrecipitationProbability)
+			}
+		}
+		fmt.Fprintf(w, "  %s: %s %s %s\n", opts.tr("Precipitation"),
+			formatValue(day.PrecipitationSum), units.Precipitation,
+			opts.precipitationProbability(day.PrecipitationProbabilityMax, opts.dailyProbability(day)))
+		fmt.Fprintf(w, "  %s: %s %s - %s: %s\n", opts.tr("Rain"), formatValue(day.RainSum), units.Precipitation,
+			opts.tr("Precipitation Hours"), formatValue(day.PrecipitationHours))
+		direction := ""
+		if day.WindDirection != nil {
+			direction = " " + opts.tr("from") + " " + weather.DegreesToCompass(*day.WindDirection)
+		}
+		// A windy day is highlighted as a whole instead of just the speed
+		speed := formatValue(day.WindSpeedMax) + " " + units.WindSpeed
+		if !day.Windy {
+			speed = opts.windSpeed(day.WindSpeedMax, speed)
+		}
+		fmt.Fprintln(w, opts.windy(day.Windy, fmt.Sprintf("  %s: %s%s%s", opts.tr("Max Wind Speed"),
+			speed, opts.gusts(day.WindSpeedMax, day.WindGustsMax, units.WindSpeed), direction)))
+		// Only snowy days get a snow line, so summer output stays the same
+		if day.SnowfallSum != nil && *day.SnowfallSum > 0 {
+			depth := ""
+			if day.SnowDepth != nil && *day.SnowDepth > 0 {
+				depth = fmt.Sprintf(" - %s: %.2f %s", opts.tr("Snow Depth"), *day.SnowDepth, units.SnowDepth)
+			}
+			fmt.Fprintf(w, "  %s: %.1f %s%s\n", opts.tr("Snowfall"), *day.SnowfallSum, units.Snowfall, depth)
+		}
+		if day.UVIndexMax != nil {
+			category, protection := weather.UVRisk(*day.UVIndexMax)
+			fmt.Fprintf(w, "  %s: %.1f (%s, %s)\n", opts.tr("Max UV Index"), *day.UVIndexMax, opts.uvCategory(category, 0), protection)
+		} else {
+			fmt.Fprintf(w, "  %s: n/a\n", opts.tr("Max UV Index"))
+		}
+
+		if day.DaytimeHumidity != nil {
+			fmt.Fprintf(w, "  %s: %.0f%%\n", opts.tr("Daytime Humidity"), *day.DaytimeHumidity)
+		}
+		if day.NightCloudCover != nil && *day.NightCloudCover < stargazingCloudCover {
+			fmt.Fprintf(w, "  "+opts.tr("Clear night for stargazing (%.0f%% cloud cover from 22:00 to 02:00)")+"\n", *day.NightCloudCover)
+		}
+		if report.Marine != nil {
+			if sea, ok := report.Marine.marineDay(day.Date); ok {
+				fmt.Fprintf(w, "  %s: %s\n", opts.tr("Sea"), opts.seaState(sea, report.Marine.Unit))
+			}
+		}
+
+		renderSun(w, day, opts)
+		fmt.Fprintln(w)
+	}
+	if opts.Compact && len(report.Daily) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	if len(report.Trend) > 0 {
+		renderGraph(w, report, opts)
+		fmt.Fprintln(w)
+	}
+
+	if opts.NoHourly {
+		return
+	}
+	if report.HourlyDay != "" {
+		label := opts.dayLabel(report.HourlyDay, report.Current.Time, 0)
+		fmt.Fprintf(w, opts.tr("Hourly Forecast for %s")+":\n", label+" ("+report.HourlyDay+")")
+	} else if report.Current.Time == "" {
+		fmt.Fprintf(w, opts.tr("Hourly Weather (%d hours)")+":\n", len(report.Hourly))
+	} else {
+		fmt.Fprintf(w, opts.tr("Hourly Forecast (next %d hours)")+":\n", len(report.Hourly))
+	}
+	if opts.Chart {
+		temperatures := make([]float64, len(report.Hourly))
+		for i, hour := range report.Hourly {
+			temperatures[i] = math.NaN()
+			if hour.Temperature != nil {
+				temperatures[i] = *hour.Temperature
+			}
+		}
+		fmt.Fprintf(w, "  %s: %s\n", opts.tr("Temperature"), sparkline(temperatures, 0, opts.ASCII))
+	}
+	// Times are padded to the width of the translated "Now (10:00)" label when it is longer
+	labelWidth := 0
+	for _, hour := range report.Hourly {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(opts.hourLabel(hour)))
+	}
+	for _, hour := range report.Hourly {
+		detail := ""
+		if opts.Detail {
+			direction := ""
+			if hour.WindDirection != nil {
+				direction = weather.DegreesToCompass(*hour.WindDirection)
+			}
+			detail = fmt.Sprintf("%s: %-8s %s: %s%s %-5s  ", opts.tr("Dew point"), formatValue(hour.DewPoint)+units.Temperature,
+				opts.tr("Wind"), opts.windSpeed(hour.WindSpeed, fmt.Sprintf("%5s %s", formatValue(hour.WindSpeed), units.WindSpeed)),
+				opts.gusts(hour.WindSpeed, hour.WindGusts, units.WindSpeed), direction)
+			// The UV index is always 0 at night, so those hours leave the column blank
+			uvLabel := opts.tr("UV")
+			uv := strings.Repeat(" ", utf8.RuneCountInString(uvLabel)+17)
+			if hour.IsDay && hour.UVIndex != nil {
+				category, _ := weather.UVRisk(*hour.UVIndex)
+				uv = fmt.Sprintf("%s: %4.1f %s", uvLabel, *hour.UVIndex, opts.uvCategory(category, 10))
+			}
+			detail += uv + "  "
+			detail += fmt.Sprintf("%s: %3s/%3s/%3s%%  %s: %4s %s  ", opts.tr("Low/Mid/High"),
+				roundValue(0, hour.CloudCoverLow), roundValue(0, hour.CloudCoverMid), roundValue(0, hour.CloudCoverHigh),
+				opts.tr("Pressure"), roundValue(0, hour.SurfacePressure), units.Pressure)
+		}
+
+		// Pad the columns so the rows line up, leaving the condition last;
+		// colors are added around the padded text so they do not change the widths
+		// Windy hours are marked by their time, which keeps the other colors readable
+		label := opts.hourLabel(hour)
+		label += strings.Repeat(" ", labelWidth-utf8.RuneCountInString(label))
+		fmt.Fprintf(w, "  %s: %s %-19s %s: %-8s %s  %s: %5s%%  %s: %3s%%  %s%s\n",
+			opts.windy(hour.Windy, label),
+			opts.temperature(hour.Temperature, units.Temperature, 8),
+			opts.feelsLike(hour.Temperature, hour.ApparentTemperature, units.Temperature),
+			opts.tr("Precipitation"), formatValue(hour.Precipitation)+" "+units.Precipitation,
+			opts.precipitationProbability(hour.PrecipitationProbability,
+				fmt.Sprintf("(%5s%% %s)", formatValue(hour.PrecipitationProbability), opts.tr("probability"))),
+			opts.tr("Humidity"), formatValue(hour.RelativeHumidity),
+			opts.tr("Clouds"), roundValue(0, hour.CloudCover),
+			detail,
+			opts.condition(hour.WeatherCode, snowDominant(hour.Snowfall, hour.Precipitation, units)))
+	}
+}
+
+// hourLabel is the time of an hourly row, or "Now (10:00)" for the hour containing the current time
+func (opts renderOptions) hourLabel(hour HourlyEntry) string {
+	if hour.Now {
+		return opts.tr("Now") + " (" + opts.clock(hour.Time) + ")"
+	}
+	return hour.Time
+}
+
+// graphWidth is the number of glyphs in a -graph sparkline
+const graphWidth = 24
+
+// renderGraph draws the temperature and precipitation probability trend with their ranges
+func renderGraph(w io.Writer, report Report, opts renderOptions) {
+	temperatures := make([]float64, len(report.Trend))
+	probabilities := make([]float64, len(report.Trend))
+	for i, hour := range report.Trend {
+		temperatures[i], probabilities[i] = math.NaN(), math.NaN()
+		if hour.Temperature != nil {
+			temperatures[i] = *hour.Temperature
+		}
+		if hour.PrecipitationProbability != nil {
+			probabilities[i] = *hour.PrecipitationProbability
+		}
+	}
+
+	fmt.Fprintf(w, opts.tr("Next %d hours")+":\n", len(report.Trend))
+	// The labels are padded to the longer one so the sparklines line up
+	temperatureLabel, precipitationLabel := opts.tr("Temperature")+":", opts.tr("Precipitation")+":"
+	width := max(utf8.RuneCountInString(temperatureLabel), utf8.RuneCountInString(precipitationLabel))
+	temperatureLabel += strings.Repeat(" ", width-utf8.RuneCountInString(temperatureLabel))
+	precipitationLabel += strings.Repeat(" ", width-utf8.RuneCountInString(precipitationLabel))
+
+	line := sparkline(temperatures, graphWidth, opts.ASCII)
+	if low, high, ok := seriesRange(temperatures); ok {
+		fmt.Fprintf(w, "  %s %s  %.1f%s %s %.1f%s\n", temperatureLabel, line, low, report.Units.Temperature, opts.tr("to"), high, report.Units.Temperature)
+	} else {
+		fmt.Fprintf(w, "  %s n/a\n", temperatureLabel)
+	}
+	line = sparkline(probabilities, graphWidth, opts.ASCII)
+	if low, high, ok := seriesRange(probabilities); ok {
+		fmt.Fprintf(w, "  %s %s  %.0f%% %s %.0f%%\n", precipitationLabel, line, low, opts.tr("to"), high)
+	} else {
+		fmt.Fprintf(w, "  %s n/a\n", precipitationLabel)
+	}
+}
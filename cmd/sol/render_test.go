@@ -0,0 +1,65 @@
+package main
+
+import "testing"
+
+func TestFormatDayCompact(t *testing.T) {
+	day := DailyEntry{
+		Date:                        "2026-06-01",
+		TemperatureMin:              ptr(11.6),
+		TemperatureMax:              ptr(21.4),
+		PrecipitationSum:            ptr(2.1),
+		PrecipitationProbabilityMax: ptr(40),
+		WindSpeedMax:                ptr(18.2),
+	}
+	undated := day
+	undated.Date = "June 1st"
+	units := ReportUnits{Temperature: "°C", Precipitation: "mm", WindSpeed: "km/h"}
+	// A locale with weekday names shorter than three letters
+	locales["xx"] = map[string]string{"Monday": "Mo"}
+	defer delete(locales, "xx")
+	tests := []struct {
+		name string
+		day  DailyEntry
+		opts renderOptions
+		want string
+	}{
+		{"emoji", day, renderOptions{}, "Mon 2026-06-01  12–21°C    ☔2.1mm(40%)       💨18km/h"},
+		{"no emoji", day, renderOptions{NoEmoji: true}, "Mon 2026-06-01  12–21°C    precip 2.1mm(40%)  wind 18km/h"},
+		{"no emoji in German", day, renderOptions{NoEmoji: true, Locale: "de"}, "Mon 2026-06-01  12–21°C    Nieders. 2.1mm(40%)  Wind 18km/h"},
+		{"no emoji in French", day, renderOptions{NoEmoji: true, Locale: "fr"}, "Lun 2026-06-01  12–21°C    précip. 2.1mm(40%)  vent 18km/h"},
+		{"short weekday name", day, renderOptions{Locale: "xx"}, "Mo  2026-06-01  12–21°C    ☔2.1mm(40%)       💨18km/h"},
+		{"unparsable date", undated, renderOptions{NoEmoji: true}, "    June 1st  12–21°C    precip 2.1mm(40%)  wind 18km/h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDayCompact(tt.day, units, tt.opts); got != tt.want {
+				t.Errorf("formatDayCompact =\n%q, want\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollenSummary(t *testing.T) {
+	pollen := PollenEntry{Available: true, Species: []PollenReading{
+		{Species: "alder", Grains: 2, Level: "Low"},
+		{Species: "birch", Grains: 120, Level: "High"},
+	}}
+	calm := PollenEntry{Available: true, Species: []PollenReading{{Species: "alder", Grains: 2, Level: "Low"}}}
+	tests := []struct {
+		name   string
+		pollen PollenEntry
+		locale string
+		want   string
+	}{
+		{"English", pollen, "en", "birch High (120 grains/m³)"},
+		{"German", pollen, "de", "birch Hoch (120 Pollen/m³)"},
+		{"all low in Spanish", calm, "es", "Bajo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (renderOptions{Locale: tt.locale}).pollenSummary(tt.pollen); got != tt.want {
+				t.Errorf("pollenSummary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
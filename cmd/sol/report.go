@@ -0,0 +1,646 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// Report is the data shown to the user, either rendered as text or marshaled as JSON
+type Report struct {
+	Location ReportLocation `json:"location"`
+	Timezone string         `json:"timezone"`
+	// Zone is Timezone loaded for converting the local times
+	Zone *time.Location `json:"-"`
+	// CacheAgeSeconds is set when the API was unreachable and older cached data is shown
+	CacheAgeSeconds int `json:"cache_age_seconds,omitempty"`
+	// Model is set with -model; Unavailable names the series it had no data for
+	// and Incomplete the series the API sent fewer values of than times
+	Model       string        `json:"model,omitempty"`
+	Unavailable []string      `json:"unavailable,omitempty"`
+	Incomplete  []string      `json:"incomplete,omitempty"`
+	Units       ReportUnits   `json:"units"`
+	Current     CurrentEntry  `json:"current"`
+	Daily       []DailyEntry  `json:"daily"`
+	Hourly      []HourlyEntry `json:"hourly"`
+	// HourlyDay is the date of the hourly rows with -hourly-day
+	HourlyDay string `json:"hourly_day,omitempty"`
+	// Trend covers the next hours drawn by -graph, independent of -hours
+	Trend []HourlyEntry `json:"trend,omitempty"`
+	// Rain is the next spell of rain, set when there are current conditions
+	Rain *RainOutlook `json:"rain,omitempty"`
+	// BestWindow is set with -best-window, nil when no window fits
+	BestWindow *OutdoorWindow `json:"best_window,omitempty"`
+	// upcoming are the hours from the current one, for markRain and markBestWindow
+	upcoming        []HourlyEntry
+	bestWindowHours int
+	// AirQuality is only set with -air-quality
+	AirQuality *AirQualityEntry `json:"air_quality,omitempty"`
+	// Pollen is only set with -pollen
+	Pollen *PollenEntry `json:"pollen,omitempty"`
+	// Marine is only set with -marine
+	Marine *MarineEntry `json:"marine,omitempty"`
+	// Ensemble is only set with -ensemble
+	Ensemble *EnsembleEntry `json:"ensemble,omitempty"`
+}
+
+// EnsembleEntry is the spread of the ensemble members over the shown days
+type EnsembleEntry struct {
+	Model string          `json:"model"`
+	Days  []EnsembleRange `json:"days"`
+}
+
+type EnsembleRange struct {
+	Date string `json:"date"`
+	// Range of the members' daily maximum temperature
+	TemperatureMaxLow  float64 `json:"temperature_max_low"`
+	TemperatureMaxHigh float64 `json:"temperature_max_high"`
+	// PrecipitationProbability is the percentage of members with measurable precipitation
+	PrecipitationProbability float64 `json:"precipitation_probability"`
+	Members                  int     `json:"members"`
+}
+
+// newEnsembleEntry aggregates the members for the days of the report. Measurable
+// precipitation is 0.1 mm, or 0.004 inches in imperial units.
+func newEnsembleEntry(response *weather.EnsembleResponse, daily []DailyEntry, units ReportUnits) *EnsembleEntry {
+	threshold := 0.1
+	if units.Precipitation == "inch" {
+		threshold = 0.004
+	}
+	entry := &EnsembleEntry{Model: weather.EnsembleModel, Days: []EnsembleRange{}}
+	for _, d := range daily {
+		day, ok := response.Day(d.Date, threshold)
+		if !ok {
+			continue
+		}
+		entry.Days = append(entry.Days, EnsembleRange{
+			Date:                     day.Date,
+			TemperatureMaxLow:        day.HighMin,
+			TemperatureMaxHigh:       day.HighMax,
+			PrecipitationProbability: day.PrecipitationChance,
+			Members:                  day.Members,
+		})
+	}
+	return entry
+}
+
+// ensembleDay returns the ensemble range of date, if there is one
+func (e *EnsembleEntry) ensembleDay(date string) (EnsembleRange, bool) {
+	for _, day := range e.Days {
+		if day.Date == date {
+			return day, true
+		}
+	}
+	return EnsembleRange{}, false
+}
+
+// MarineEntry is the sea state of the shown days. Available is false when the
+// location is away from the ocean grid of the marine models.
+type MarineEntry struct {
+	Available bool   `json:"available"`
+	Unit      string `json:"unit,omitempty"`
+	// Days has one entry per shown day, in the order of Daily
+	Days []MarineDay `json:"days"`
+}
+
+type MarineDay struct {
+	Date           string   `json:"date"`
+	WaveHeightMax  *float64 `json:"wave_height_max"`
+	WaveDirection  *float64 `json:"wave_direction"`
+	WavePeriodMax  *float64 `json:"wave_period_max"`
+	SwellHeightMax *float64 `json:"swell_wave_height_max"`
+}
+
+// newMarineEntry picks the marine days matching the days of the report
+func newMarineEntry(response *weather.MarineResponse, daily []DailyEntry) *MarineEntry {
+	entry := &MarineEntry{Available: response.HasData(), Unit: response.DailyUnits.WaveHeightMax, Days: []MarineDay{}}
+	if !entry.Available {
+		return entry
+	}
+
+	marineDays := response.DailySlots()
+	for _, day := range daily {
+		for _, marine := range marineDays {
+			if marine.Date != day.Date {
+				continue
+			}
+			entry.Days = append(entry.Days, MarineDay{
+				Date:           marine.Date,
+				WaveHeightMax:  marine.WaveHeightMax,
+				WaveDirection:  marine.WaveDirection,
+				WavePeriodMax:  marine.WavePeriodMax,
+				SwellHeightMax: marine.SwellWaveHeightMax,
+			})
+		}
+	}
+	return entry
+}
+
+// marineDay returns the sea state of date, if there is one
+func (m *MarineEntry) marineDay(date string) (MarineDay, bool) {
+	for _, day := range m.Days {
+		if day.Date == date {
+			return day, true
+		}
+	}
+	return MarineDay{}, false
+}
+
+// PollenEntry holds the current pollen counts. Available is false outside the
+// area the pollen models cover, where the API only has nulls.
+type PollenEntry struct {
+	Time      string          `json:"time"`
+	Available bool            `json:"available"`
+	Species   []PollenReading `json:"species"`
+}
+
+type PollenReading struct {
+	Species string  `json:"species"`
+	Grains  float64 `json:"grains_per_m3"`
+	// Level is "Low", "Medium" or "High"
+	Level string `json:"level"`
+}
+
+// newPollenEntry collects the species that have a count
+func newPollenEntry(response *weather.PollenResponse) *PollenEntry {
+	entry := &PollenEntry{Time: response.Current.Time, Species: []PollenReading{}}
+	counts := response.Counts()
+	for _, species := range weather.PollenSpecies {
+		grains := counts[species]
+		if grains == nil {
+			continue
+		}
+		entry.Available = true
+		entry.Species = append(entry.Species, PollenReading{Species: species, Grains: *grains, Level: weather.PollenLevel(species, *grains)})
+	}
+	return entry
+}
+
+// AirQualityEntry is the current air quality; particulate matter and ozone are in µg/m³
+type AirQualityEntry struct {
+	Time        string   `json:"time"`
+	PM25        *float64 `json:"pm2_5"`
+	PM10        *float64 `json:"pm10"`
+	Ozone       *float64 `json:"ozone"`
+	EuropeanAQI *float64 `json:"european_aqi"`
+	// Category is the band of the European AQI, like "Fair"
+	Category string   `json:"category,omitempty"`
+	USAQI    *float64 `json:"us_aqi"`
+	// USCategory is the band of the US AQI, like "Moderate"
+	USCategory string `json:"us_category,omitempty"`
+	// WorstHour is the local time of the highest US AQI today, with that index
+	WorstHour  string   `json:"worst_hour,omitempty"`
+	WorstUSAQI *float64 `json:"worst_us_aqi,omitempty"`
+}
+
+// newAirQualityEntry takes the current reading of an air quality response and
+// finds the worst hour of the current day
+func newAirQualityEntry(response *weather.AirQualityResponse) *AirQualityEntry {
+	current := response.Current
+	entry := &AirQualityEntry{
+		Time:        current.Time,
+		PM25:        current.PM25,
+		PM10:        current.PM10,
+		Ozone:       current.Ozone,
+		EuropeanAQI: current.EuropeanAQI,
+		USAQI:       current.USAQI,
+	}
+	if current.EuropeanAQI != nil {
+		entry.Category = weather.EuropeanAQICategory(*current.EuropeanAQI)
+	}
+	if current.USAQI != nil {
+		entry.USCategory = weather.USAQICategory(*current.USAQI)
+	}
+
+	today, _, _ := strings.Cut(current.Time, "T")
+	for _, hour := range response.HourlySlots() {
+		if today == "" || !strings.HasPrefix(hour.Time, today+"T") || hour.USAQI == nil {
+			continue
+		}
+		if entry.WorstUSAQI == nil || *hour.USAQI > *entry.WorstUSAQI {
+			entry.WorstHour, entry.WorstUSAQI = hour.Time, hour.USAQI
+		}
+	}
+	return entry
+}
+
+type ReportLocation struct {
+	Name      string  `json:"name,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type ReportUnits struct {
+	Temperature   string `json:"temperature"`
+	Precipitation string `json:"precipitation"`
+	WindSpeed     string `json:"wind_speed"`
+	Snowfall      string `json:"snowfall"`
+	SnowDepth     string `json:"snow_depth"`
+	Pressure      string `json:"pressure"`
+}
+
+type CurrentEntry struct {
+	Time                string              `json:"time"`
+	Temperature         float64             `json:"temperature"`
+	ApparentTemperature float64             `json:"apparent_temperature"`
+	WeatherCode         weather.WeatherCode `json:"weather_code"`
+	WindSpeed           float64             `json:"wind_speed"`
+	WindDirection       float64             `json:"wind_direction"`
+	RelativeHumidity    float64             `json:"relative_humidity"`
+	IsDay               bool                `json:"is_day"`
+	// Sea level pressure in hPa, with its trend over the last 3 hours like "↑ rising"
+	Pressure      float64 `json:"pressure"`
+	PressureTrend string  `json:"pressure_trend"`
+}
+
+// FailedReport stands in for a location whose forecast could not be fetched
+type FailedReport struct {
+	Location ReportLocation `json:"location"`
+	Error    string         `json:"error"`
+}
+
+// Values are pointers so that anything missing from the API response is encoded as null
+type DailyEntry struct {
+	Date                         string   `json:"date"`
+	TemperatureMin               *float64 `json:"temperature_min"`
+	TemperatureMax               *float64 `json:"temperature_max"`
+	ApparentTemperatureMin       *float64 `json:"apparent_temperature_min"`
+	ApparentTemperatureMax       *float64 `json:"apparent_temperature_max"`
+	PrecipitationSum             *float64 `json:"precipitation_sum"`
+	PrecipitationProbabilityMax  *float64 `json:"precipitation_probability_max"`
+	PrecipitationProbabilityMean *float64 `json:"precipitation_probability_mean"`
+	RainSum                      *float64 `json:"rain_sum"`
+	PrecipitationHours           *float64 `json:"precipitation_hours"`
+	SnowfallSum                  *float64 `json:"snowfall_sum"`
+	// Deepest snow on the ground during the day, from the hourly values
+	SnowDepth *float64 `json:"snow_depth"`
+	// Mean cloud cover of the night from 22:00 to 02:00 the next day, from the hourly values
+	NightCloudCover *float64 `json:"night_cloud_cover"`
+	WindSpeedMax    *float64 `json:"wind_speed_max"`
+	WindGustsMax    *float64 `json:"wind_gusts_max"`
+	// Degrees the wind mostly blows from
+	WindDirection *float64             `json:"wind_direction"`
+	WeatherCode   *weather.WeatherCode `json:"weather_code"`
+	// Local ISO times; empty when the sun does not rise or set that day
+	Sunrise string `json:"sunrise,omitempty"`
+	Sunset  string `json:"sunset,omitempty"`
+	// Seconds of daylight
+	DaylightDuration *float64 `json:"daylight_duration"`
+	UVIndexMax       *float64 `json:"uv_index_max"`
+	// Mean relative humidity from 09:00 to 18:00, from the hourly values
+	DaytimeHumidity *float64 `json:"daytime_relative_humidity"`
+	// Windy is set when the wind or gusts reach the -wind-warn threshold
+	Windy bool `json:"windy"`
+	// Frost is set when the low falls below the -frost-threshold
+	Frost bool `json:"frost"`
+}
+
+type HourlyEntry struct {
+	Time                     string               `json:"time"`
+	Temperature              *float64             `json:"temperature"`
+	ApparentTemperature      *float64             `json:"apparent_temperature"`
+	Precipitation            *float64             `json:"precipitation"`
+	PrecipitationProbability *float64             `json:"precipitation_probability"`
+	WeatherCode              *weather.WeatherCode `json:"weather_code"`
+	RelativeHumidity         *float64             `json:"relative_humidity"`
+	DewPoint                 *float64             `json:"dew_point"`
+	WindSpeed                *float64             `json:"wind_speed"`
+	WindDirection            *float64             `json:"wind_direction"`
+	WindGusts                *float64             `json:"wind_gusts"`
+	UVIndex                  *float64             `json:"uv_index"`
+	Snowfall                 *float64             `json:"snowfall"`
+	SnowDepth                *float64             `json:"snow_depth"`
+	CloudCover               *float64             `json:"cloud_cover"`
+	CloudCoverLow            *float64             `json:"cloud_cover_low"`
+	CloudCoverMid            *float64             `json:"cloud_cover_mid"`
+	CloudCoverHigh           *float64             `json:"cloud_cover_high"`
+	SurfacePressure          *float64             `json:"surface_pressure"`
+	IsDay                    bool                 `json:"is_day"`
+	Windy                    bool                 `json:"windy"`
+	// Now marks the hour that contains the current time
+	Now bool `json:"now,omitempty"`
+}
+
+// Daytime runs from daytimeStart up to and including daytimeEnd, in local hours
+const (
+	daytimeStart = 9
+	daytimeEnd   = 18
+)
+
+// daytimeAverage averages value over the hours on date between daytimeStart and
+// daytimeEnd, returning nil when there are none. The API has no daily humidity
+// aggregate, so it is computed here.
+func daytimeAverage(hours []weather.HourlySlot, value func(weather.HourlySlot) *float64, date string) *float64 {
+	sum, count := 0.0, 0
+	for _, hour := range hours {
+		day, clock, ok := strings.Cut(hour.Time, "T")
+		v := value(hour)
+		if !ok || day != date || v == nil {
+			continue
+		}
+		h, err := strconv.Atoi(strings.SplitN(clock, ":", 2)[0])
+		if err != nil || h < daytimeStart || h > daytimeEnd {
+			continue
+		}
+		sum += *v
+		count++
+	}
+
+	if count == 0 {
+		return nil
+	}
+	average := sum / float64(count)
+	return &average
+}
+
+// The night of a day runs from nightStart on that day for nightHours hours,
+// into the small hours of the next day
+const (
+	nightStart = 22
+	nightHours = 4
+)
+
+// nightAverage averages value from nightStart on date up to and including
+// nightStart+nightHours, which falls on the next day. It returns nil unless every hour
+// of the night has a value, so the last day does not get a verdict from half a night.
+func nightAverage(hours []weather.HourlySlot, value func(weather.HourlySlot) *float64, date string) *float64 {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+	// Local ISO times sort like the times they stand for
+	start := day.Add(nightStart * time.Hour)
+	first := start.Format("2006-01-02T15:04")
+	last := start.Add(nightHours * time.Hour).Format("2006-01-02T15:04")
+
+	sum, count := 0.0, 0
+	for _, hour := range hours {
+		v := value(hour)
+		if hour.Time < first || hour.Time > last || v == nil {
+			continue
+		}
+		sum += *v
+		count++
+	}
+
+	if count < nightHours+1 {
+		return nil
+	}
+	average := sum / float64(count)
+	return &average
+}
+
+// pressureSteady is the change in hPa over pressureTrendHours that still counts as steady
+const (
+	pressureSteady     = 1.0
+	pressureTrendHours = 3
+)
+
+// pressureTrend compares the sea level pressure of the hour at idx with pressureTrendHours
+// earlier and returns "↑ rising", "→ steady" or "↓ falling". Near the start of the series
+// the earliest hour stands in, and at its very start the next hours are used instead.
+// It returns "" when there is nothing to compare.
+func pressureTrend(hours []weather.HourlySlot, idx int) string {
+	if idx < 0 || idx >= len(hours) {
+		return ""
+	}
+	from, to := max(idx-pressureTrendHours, 0), idx
+	if from == to {
+		to = min(idx+pressureTrendHours, len(hours)-1)
+	}
+	if from == to || hours[from].PressureMSL == nil || hours[to].PressureMSL == nil {
+		return ""
+	}
+
+	switch change := *hours[to].PressureMSL - *hours[from].PressureMSL; {
+	case change >= pressureSteady:
+		return "↑ rising"
+	case change <= -pressureSteady:
+		return "↓ falling"
+	default:
+		return "→ steady"
+	}
+}
+
+// dailyMax returns the largest value of the hours on date, or nil when there are none
+func dailyMax(hours []weather.HourlySlot, value func(weather.HourlySlot) *float64, date string) *float64 {
+	var largest *float64
+	for _, hour := range hours {
+		v := value(hour)
+		if !strings.HasPrefix(hour.Time, date+"T") || v == nil {
+			continue
+		}
+		if largest == nil || *v > *largest {
+			largest = v
+		}
+	}
+	return largest
+}
+
+// snowDominant reports whether snow makes up most of the precipitation. Snowfall is
+// the depth of fresh snow: 7 cm of snow hold about 10 mm of water, 7 inches about 1 inch.
+func snowDominant(snowfall, precipitation *float64, units ReportUnits) bool {
+	if snowfall == nil || precipitation == nil || *snowfall <= 0 || *precipitation <= 0 {
+		return false
+	}
+	water := *snowfall / 7
+	if units.Snowfall == "cm" {
+		water = *snowfall * 10 / 7
+	}
+	return water >= *precipitation/2
+}
+
+// buildReport selects the requested days and the hours starting from the current one.
+// trendHours is how many hours to collect for the -graph trend, 0 for none.
+func buildReport(response *weather.WeatherResponse, days, hours, trendHours int, fromNextHour bool) Report {
+	report := Report{
+		Location: ReportLocation{
+			Latitude:  response.Latitude,
+			Longitude: response.Longitude,
+		},
+		Timezone:        response.Timezone,
+		Zone:            response.TimeLocation(),
+		CacheAgeSeconds: int(response.CacheAge.Seconds()),
+		Unavailable:     response.Unavailable,
+		Incomplete:      response.Incomplete,
+		Units: ReportUnits{
+			Temperature:   response.HourlyUnits.Temperature2m,
+			Precipitation: response.HourlyUnits.Precipitation,
+			WindSpeed:     response.HourlyUnits.WindSpeed10m,
+			Snowfall:      response.HourlyUnits.Snowfall,
+			SnowDepth:     response.HourlyUnits.SnowDepth,
+			Pressure:      response.HourlyUnits.SurfacePressure,
+		},
+		Current: CurrentEntry{
+			Time:                response.Current.Time,
+			Temperature:         response.Current.Temperature2m,
+			ApparentTemperature: response.Current.ApparentTemperature,
+			WeatherCode:         response.Current.WeatherCode,
+			WindSpeed:           response.Current.WindSpeed10m,
+			WindDirection:       response.Current.WindDirection10m,
+			RelativeHumidity:    response.Current.RelativeHumidity2m,
+			IsDay:               response.Current.IsDay == 1,
+			Pressure:            response.Current.PressureMSL,
+		},
+		Daily:  []DailyEntry{},
+		Hourly: []HourlyEntry{},
+	}
+
+	hourly := response.HourlySlots()
+	for _, day := range response.DailySlots()[:min(days, len(response.Daily.Time))] {
+		report.Daily = append(report.Daily, DailyEntry{
+			Date:                         day.Date,
+			TemperatureMin:               day.TemperatureMin,
+			TemperatureMax:               day.TemperatureMax,
+			ApparentTemperatureMin:       day.ApparentTemperatureMin,
+			ApparentTemperatureMax:       day.ApparentTemperatureMax,
+			PrecipitationSum:             day.PrecipitationSum,
+			PrecipitationProbabilityMax:  day.PrecipitationProbabilityMax,
+			PrecipitationProbabilityMean: day.PrecipitationProbabilityMean,
+			RainSum:                      day.RainSum,
+			PrecipitationHours:           day.PrecipitationHours,
+			SnowfallSum:                  day.SnowfallSum,
+			SnowDepth:                    dailyMax(hourly, func(h weather.HourlySlot) *float64 { return h.SnowDepth }, day.Date),
+			NightCloudCover:              nightAverage(hourly, func(h weather.HourlySlot) *float64 { return h.CloudCover }, day.Date),
+			WindSpeedMax:                 day.WindSpeedMax,
+			WindGustsMax:                 day.WindGustsMax,
+			WindDirection:                day.WindDirection,
+			WeatherCode:                  day.WeatherCode,
+			Sunrise:                      day.Sunrise,
+			Sunset:                       day.Sunset,
+			DaylightDuration:             day.DaylightDuration,
+			UVIndexMax:                   day.UVIndexMax,
+			DaytimeHumidity:              daytimeAverage(hourly, func(h weather.HourlySlot) *float64 { return h.RelativeHumidity }, day.Date),
+		})
+	}
+
+	// Find the current hour index
+	currentIndex, err := response.CurrentHourIndex(fromNextHour)
+	if err != nil {
+		logf("Warning: Could not determine current time, showing from beginning: %v", err)
+		currentIndex = 0
+	}
+	report.Current.PressureTrend = pressureTrend(hourly, currentIndex)
+
+	// Make sure we don't go beyond available data
+	hoursToShow := hours
+	if currentIndex+hoursToShow > len(hourly) {
+		hoursToShow = len(hourly) - currentIndex
+		logf("Note: only %d of the %d requested hours are available", hoursToShow, hours)
+	}
+
+	for _, hour := range hourly[currentIndex : currentIndex+hoursToShow] {
+		report.Hourly = append(report.Hourly, newHourlyEntry(hour))
+	}
+	// Label the row of the hour bucket that contains the current time, if it is shown
+	nowHour := time.Now().In(report.Zone).Format("2006-01-02T15")
+	for i := range report.Hourly {
+		report.Hourly[i].Now = strings.HasPrefix(report.Hourly[i].Time, nowHour+":")
+	}
+
+	for _, hour := range hourly[currentIndex:min(currentIndex+trendHours, len(hourly))] {
+		report.Trend = append(report.Trend, newHourlyEntry(hour))
+	}
+	// The archive has no current hour to look ahead from
+	if report.Current.Time != "" {
+		for _, hour := range hourly[currentIndex:min(currentIndex+max(rainLookahead, bestWindowHorizon), len(hourly))] {
+			entry := newHourlyEntry(hour)
+			entry.Now = strings.HasPrefix(entry.Time, nowHour+":")
+			report.upcoming = append(report.upcoming, entry)
+		}
+	}
+
+	return report
+}
+
+// dayAfter returns the date offset days after today at the location
+func (r *Report) dayAfter(offset int) string {
+	today, err := time.Parse("2006-01-02", strings.SplitN(r.Current.Time, "T", 2)[0])
+	if err != nil {
+		today = time.Now().In(r.Zone)
+	}
+	return today.AddDate(0, 0, offset).Format("2006-01-02")
+}
+
+// selectHourlyDay replaces the hourly rows with the hours of the day offset days after today
+func (r *Report) selectHourlyDay(response *weather.WeatherResponse, offset int) {
+	r.HourlyDay = r.dayAfter(offset)
+
+	r.Hourly = nil
+	nowHour := time.Now().In(r.Zone).Format("2006-01-02T15")
+	for _, hour := range response.HourlySlots() {
+		if !strings.HasPrefix(hour.Time, r.HourlyDay+"T") {
+			continue
+		}
+		entry := newHourlyEntry(hour)
+		entry.Now = strings.HasPrefix(hour.Time, nowHour+":")
+		r.Hourly = append(r.Hourly, entry)
+	}
+}
+
+// newHourlyEntry copies the values of an hour of the forecast
+func newHourlyEntry(hour weather.HourlySlot) HourlyEntry {
+	return HourlyEntry{
+		Time:                     hour.Time,
+		Temperature:              hour.Temperature,
+		ApparentTemperature:      hour.ApparentTemperature,
+		Precipitation:            hour.Precipitation,
+		PrecipitationProbability: hour.PrecipitationProbability,
+		WeatherCode:              hour.WeatherCode,
+		RelativeHumidity:         hour.RelativeHumidity,
+		DewPoint:                 hour.DewPoint,
+		WindSpeed:                hour.WindSpeed,
+		WindDirection:            hour.WindDirection,
+		WindGusts:                hour.WindGusts,
+		UVIndex:                  hour.UVIndex,
+		Snowfall:                 hour.Snowfall,
+		SnowDepth:                hour.SnowDepth,
+		CloudCover:               hour.CloudCover,
+		CloudCoverLow:            hour.CloudCoverLow,
+		CloudCoverMid:            hour.CloudCoverMid,
+		CloudCoverHigh:           hour.CloudCoverHigh,
+		SurfacePressure:          hour.SurfacePressure,
+		IsDay:                    hour.IsDay,
+	}
+}
+
+// isWindy reports whether the sustained wind or the gusts reach threshold; 0 turns the warning off
+func isWindy(speed, gusts *float64, threshold float64) bool {
+	if threshold <= 0 {
+		return false
+	}
+	return (speed != nil && *speed >= threshold) || (gusts != nil && *gusts >= threshold)
+}
+
+// markWindy flags the days and hours whose wind reaches the -wind-warn threshold
+func (r *Report) markWindy(threshold float64) {
+	for i := range r.Daily {
+		r.Daily[i].Windy = isWindy(r.Daily[i].WindSpeedMax, r.Daily[i].WindGustsMax, threshold)
+	}
+	for i := range r.Hourly {
+		r.Hourly[i].Windy = isWindy(r.Hourly[i].WindSpeed, r.Hourly[i].WindGusts, threshold)
+	}
+	for i := range r.Trend {
+		r.Trend[i].Windy = isWindy(r.Trend[i].WindSpeed, r.Trend[i].WindGusts, threshold)
+	}
+}
+
+// markFrost flags the days whose low falls below the -frost-threshold
+func (r *Report) markFrost(threshold float64) {
+	for i := range r.Daily {
+		r.Daily[i].Frost = r.Daily[i].TemperatureMin != nil && *r.Daily[i].TemperatureMin < threshold
+	}
+}
+
+// frostAhead reports whether frost is forecast for today or a later day
+func (r *Report) frostAhead() bool {
+	today, _, _ := strings.Cut(r.Current.Time, "T")
+	for _, day := range r.Daily {
+		if day.Frost && day.Date >= today {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// temperatureSlots returns consecutive hours from start (local ISO time) with the
+// values as temperatures; NaN stands for a missing value
+func temperatureSlots(t *testing.T, start string, values ...float64) []weather.HourlySlot {
+	t.Helper()
+	at, err := time.Parse("2006-01-02T15:04", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slots := make([]weather.HourlySlot, len(values))
+	for i, v := range values {
+		slots[i].At = at.Add(time.Duration(i) * time.Hour)
+		slots[i].Time = slots[i].At.Format("2006-01-02T15:04")
+		if !math.IsNaN(v) {
+			slots[i].Temperature = &v
+		}
+	}
+	return slots
+}
+
+// hourNumbers returns n values counting up from 0, one per hour
+func hourNumbers(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	return values
+}
+
+// ptr returns a pointer to v for expected values
+func ptr(v float64) *float64 { return &v }
+
+func temperature(hour weather.HourlySlot) *float64 { return hour.Temperature }
+
+// formatAverage shows an average for test messages, with nil as n/a
+func formatAverage(v *float64) string {
+	if v == nil {
+		return "n/a"
+	}
+	return strconv.FormatFloat(*v, 'g', -1, 64)
+}
+
+func TestDaytimeAverage(t *testing.T) {
+	nan := math.NaN()
+	twoDays := hourNumbers(48)
+	gaps := hourNumbers(24)
+	for h := 9; h <= 12; h++ {
+		gaps[h] = nan
+	}
+	tests := []struct {
+		name  string
+		hours []weather.HourlySlot
+		date  string
+		want  *float64
+	}{
+		// The value is the hour, so 9:00 to 18:00 average to 13.5
+		{"whole day", temperatureSlots(t, "2026-06-01T00:00", hourNumbers(24)...), "2026-06-01", ptr(13.5)},
+		{"second day", temperatureSlots(t, "2026-06-01T00:00", twoDays...), "2026-06-02", ptr(37.5)},
+		{"missing values are skipped", temperatureSlots(t, "2026-06-01T00:00", gaps...), "2026-06-01", ptr(15.5)},
+		{"only the evening", temperatureSlots(t, "2026-06-01T17:00", 4, 6, 100, 100), "2026-06-01", ptr(5)},
+		{"no daytime hours", temperatureSlots(t, "2026-06-01T19:00", 1, 2, 3), "2026-06-01", nil},
+		{"another day", temperatureSlots(t, "2026-06-01T00:00", hourNumbers(24)...), "2026-06-03", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := daytimeAverage(tt.hours, temperature, tt.date)
+			if formatAverage(got) != formatAverage(tt.want) {
+				t.Errorf("daytimeAverage on %s = %s, want %s", tt.date, formatAverage(got), formatAverage(tt.want))
+			}
+		})
+	}
+}
+
+func TestNightAverage(t *testing.T) {
+	nan := math.NaN()
+	twoDays := hourNumbers(48)
+	gap := hourNumbers(48)
+	gap[24] = nan
+	tests := []struct {
+		name  string
+		hours []weather.HourlySlot
+		date  string
+		want  *float64
+	}{
+		// 22:00 to 02:00 are hours 22 to 26, which average to 24
+		{"across midnight", temperatureSlots(t, "2026-06-01T00:00", twoDays...), "2026-06-01", ptr(24)},
+		{"across the end of a month", temperatureSlots(t, "2026-06-30T00:00", twoDays...), "2026-06-30", ptr(24)},
+		{"across the end of a year", temperatureSlots(t, "2026-12-31T00:00", twoDays...), "2026-12-31", ptr(24)},
+		{"just the night", temperatureSlots(t, "2026-06-01T22:00", 10, 8, 6, 4, 2), "2026-06-01", ptr(6)},
+		{"a missing hour", temperatureSlots(t, "2026-06-01T00:00", gap...), "2026-06-01", nil},
+		{"half a night at the end", temperatureSlots(t, "2026-06-01T00:00", twoDays...), "2026-06-02", nil},
+		{"an invalid date", temperatureSlots(t, "2026-06-01T00:00", twoDays...), "June 1st", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nightAverage(tt.hours, temperature, tt.date)
+			if formatAverage(got) != formatAverage(tt.want) {
+				t.Errorf("nightAverage on %s = %s, want %s", tt.date, formatAverage(got), formatAverage(tt.want))
+			}
+		})
+	}
+}
+
+// pressureSlots returns consecutive hours with the values as sea level pressures
+func pressureSlots(t *testing.T, values ...float64) []weather.HourlySlot {
+	t.Helper()
+	slots := temperatureSlots(t, "2026-06-01T00:00", values...)
+	for i := range slots {
+		slots[i].PressureMSL, slots[i].Temperature = slots[i].Temperature, nil
+	}
+	return slots
+}
+
+func TestPressureTrend(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		values []float64
+		idx    int
+		want   string
+	}{
+		{"rising", []float64{1010, 1010, 1011, 1012}, 3, "↑ rising"},
+		{"rising by exactly the steady limit", []float64{1010, 1010.5, 1010.5, 1011}, 3, "↑ rising"},
+		{"steady", []float64{1010, 1009.5, 1010.2, 1010.9}, 3, "→ steady"},
+		{"falling", []float64{1015, 1014, 1012, 1011}, 3, "↓ falling"},
+		{"falling by exactly the steady limit", []float64{1015, 1015, 1015, 1014}, 3, "↓ falling"},
+		{"three hours back, not further", []float64{1000, 1010, 1010, 1010, 1010.5}, 4, "→ steady"},
+		{"near the start", []float64{1010, 1012, 1013}, 1, "↑ rising"},
+		{"at the start", []float64{1010, 1009, 1008, 1007, 1020}, 0, "↓ falling"},
+		{"a single hour", []float64{1010}, 0, ""},
+		{"a missing pressure", []float64{nan, 1010, 1011, 1012}, 3, ""},
+		{"out of range", []float64{1010, 1011}, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pressureTrend(pressureSlots(t, tt.values...), tt.idx); got != tt.want {
+				t.Errorf("pressureTrend(%v, %d) = %q, want %q", tt.values, tt.idx, got, tt.want)
+			}
+		})
+	}
+}
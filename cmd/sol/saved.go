@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// savedLocation is a named place stored with -save-location
+type savedLocation struct {
+	Name      string  `json:"name,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// savedLocationsPath keeps the saved locations next to the config file
+func savedLocationsPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "locations.json")
+}
+
+// loadSavedLocations reads the saved locations; a missing file means there are none
+func loadSavedLocations(path string) (map[string]savedLocation, error) {
+	saved := map[string]savedLocation{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading saved locations: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("error parsing saved locations in %s: %w", path, err)
+	}
+	return saved, nil
+}
+
+// writeSavedLocations replaces the file atomically so concurrent runs never see it half written
+func writeSavedLocations(path string, saved map[string]savedLocation) error {
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding saved locations: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error creating config directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "locations-*.json")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing saved locations: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing saved locations: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error replacing saved locations: %w", err)
+	}
+	return nil
+}
+
+// savedLocationNames returns the names in alphabetical order
+func savedLocationNames(saved map[string]savedLocation) []string {
+	names := make([]string, 0, len(saved))
+	for name := range saved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupSavedLocation finds a saved location, listing the known names when it is missing
+func lookupSavedLocation(saved map[string]savedLocation, name string) (savedLocation, error) {
+	loc, ok := saved[name]
+	if ok {
+		return loc, nil
+	}
+
+	if len(saved) == 0 {
+		return loc, fmt.Errorf("unknown location %q, no locations have been saved yet", name)
+	}
+	return loc, fmt.Errorf("unknown location %q, available: %s", name, strings.Join(savedLocationNames(saved), ", "))
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// templateView is what -format templates are executed against. Missing values
+// are nil; the helper functions print them as "n/a".
+type templateView struct {
+	Location ReportLocation
+	Timezone string
+	Units    ReportUnits
+	Current  templateCurrent
+	Daily    []templateDay
+	Hourly   []templateHour
+}
+
+type templateCurrent struct {
+	Time      string
+	Temp      float64
+	FeelsLike float64
+	Condition weather.WeatherCode
+	Wind      float64
+	WindDir   float64
+	Humidity  float64
+	Pressure  float64
+	// PressureTrend is "↑ rising", "→ steady" or "↓ falling"
+	PressureTrend string
+	IsDay         bool
+}
+
+type templateDay struct {
+	Date           string
+	Min            *float64
+	Max            *float64
+	Precip         *float64
+	PrecipProb     *float64
+	PrecipProbMean *float64
+	Snow           *float64
+	SnowDepth      *float64
+	Wind           *float64
+	Gusts          *float64
+	Windy          bool
+	Condition      *weather.WeatherCode
+	Sunrise        string
+	Sunset         string
+	UV             *float64
+	Humidity       *float64
+	NightClouds    *float64
+}
+
+type templateHour struct {
+	Time       string
+	Temp       *float64
+	FeelsLike  *float64
+	Precip     *float64
+	PrecipProb *float64
+	Humidity   *float64
+	DewPoint   *float64
+	Wind       *float64
+	WindDir    *float64
+	Gusts      *float64
+	Windy      bool
+	UV         *float64
+	Snow       *float64
+	SnowDepth  *float64
+	Clouds     *float64
+	Pressure   *float64
+	Condition  *weather.WeatherCode
+}
+
+// newTemplateView flattens a report into the short field names used by templates
+func newTemplateView(report Report) templateView {
+	view := templateView{
+		Location: report.Location,
+		Timezone: report.Timezone,
+		Units:    report.Units,
+		Current: templateCurrent{
+			Time:          report.Current.Time,
+			Temp:          report.Current.Temperature,
+			FeelsLike:     report.Current.ApparentTemperature,
+			Condition:     report.Current.WeatherCode,
+			Wind:          report.Current.WindSpeed,
+			WindDir:       report.Current.WindDirection,
+			Humidity:      report.Current.RelativeHumidity,
+			Pressure:      report.Current.Pressure,
+			PressureTrend: report.Current.PressureTrend,
+			IsDay:         report.Current.IsDay,
+		},
+	}
+	for _, day := range report.Daily {
+		view.Daily = append(view.Daily, templateDay{
+			Date:           day.Date,
+			Min:            day.TemperatureMin,
+			Max:            day.TemperatureMax,
+			Precip:         day.PrecipitationSum,
+			PrecipProb:     day.PrecipitationProbabilityMax,
+			PrecipProbMean: day.PrecipitationProbabilityMean,
+			Snow:           day.SnowfallSum,
+			SnowDepth:      day.SnowDepth,
+			Wind:           day.WindSpeedMax,
+			Gusts:          day.WindGustsMax,
+			Windy:          day.Windy,
+			Condition:      day.WeatherCode,
+			Sunrise:        day.Sunrise,
+			Sunset:         day.Sunset,
+			UV:             day.UVIndexMax,
+			Humidity:       day.DaytimeHumidity,
+			NightClouds:    day.NightCloudCover,
+		})
+	}
+	for _, hour := range report.Hourly {
+		view.Hourly = append(view.Hourly, templateHour{
+			Time:       hour.Time,
+			Temp:       hour.Temperature,
+			FeelsLike:  hour.ApparentTemperature,
+			Precip:     hour.Precipitation,
+			PrecipProb: hour.PrecipitationProbability,
+			Humidity:   hour.RelativeHumidity,
+			DewPoint:   hour.DewPoint,
+			Wind:       hour.WindSpeed,
+			WindDir:    hour.WindDirection,
+			Gusts:      hour.WindGusts,
+			Windy:      hour.Windy,
+			UV:         hour.UVIndex,
+			Snow:       hour.Snowfall,
+			SnowDepth:  hour.SnowDepth,
+			Clouds:     hour.CloudCover,
+			Pressure:   hour.SurfacePressure,
+			Condition:  hour.WeatherCode,
+		})
+	}
+	return view
+}
+
+// templateNumber accepts the float64 and *float64 fields of the view
+func templateNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, !math.IsNaN(n)
+	case *float64:
+		if n == nil {
+			return 0, false
+		}
+		return *n, !math.IsNaN(*n)
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// roundValue formats v with the given number of decimals, or "n/a" when it is missing
+func roundValue(places int, v any) string {
+	n, ok := templateNumber(v)
+	if !ok {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.*f", places, n)
+}
+
+// templateCode accepts the WeatherCode and *WeatherCode fields of the view
+func templateCode(v any) (weather.WeatherCode, bool) {
+	switch c := v.(type) {
+	case weather.WeatherCode:
+		return c, true
+	case *weather.WeatherCode:
+		if c == nil {
+			return 0, false
+		}
+		return *c, true
+	}
+	return 0, false
+}
+
+// templateFuncs returns the helpers available in templates. The unit helpers
+// use the units of report, so they are rebound before every execution.
+func templateFuncs(report Report, opts renderOptions) template.FuncMap {
+	withUnit := func(unit string) func(any) string {
+		return func(v any) string {
+			if _, ok := templateNumber(v); !ok {
+				return "n/a"
+			}
+			return roundValue(0, v) + unit
+		}
+	}
+
+	return template.FuncMap{
+		"round":  roundValue,
+		"temp":   withUnit(report.Units.Temperature),
+		"speed":  withUnit(" " + report.Units.WindSpeed),
+		"precip": withUnit(" " + report.Units.Precipitation),
+		"icon": func(v any) string {
+			if code, ok := templateCode(v); ok {
+				return code.Emoji()
+			}
+			return "n/a"
+		},
+		"describe": func(v any) string {
+			if code, ok := templateCode(v); ok {
+				return code.String()
+			}
+			return "n/a"
+		},
+		"clock": opts.clock,
+		"compass": func(v any) string {
+			if deg, ok := templateNumber(v); ok {
+				return weather.DegreesToCompass(deg)
+			}
+			return "n/a"
+		},
+	}
+}
+
+// parseOutputTemplate parses a -format template, with placeholder helpers until a report is bound
+func parseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs(Report{}, renderOptions{})).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w\n%s", err, templateHelp())
+	}
+	return tmpl, nil
+}
+
+// renderTemplate executes tmpl for report, ending the output with a newline
+func renderTemplate(tmpl *template.Template, report Report, opts renderOptions) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(templateFuncs(report, opts)).Execute(&buf, newTemplateView(report)); err != nil {
+		return "", fmt.Errorf("%w\n%s", err, templateHelp())
+	}
+
+	output := buf.String()
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return output, nil
+}
+
+// templateHelp lists the fields a template can use, for error messages
+func templateHelp() string {
+	var lines []string
+	view := reflect.TypeOf(templateView{})
+	for i := 0; i < view.NumField(); i++ {
+		field := view.Field(i)
+		line := "  ." + field.Name
+
+		// Describe the element type of lists and the fields of nested structs
+		inner := field.Type
+		if inner.Kind() == reflect.Slice {
+			line += " (list, e.g. index ." + field.Name + " 0)"
+			inner = inner.Elem()
+		}
+		if inner.Kind() == reflect.Struct {
+			var names []string
+			for j := 0; j < inner.NumField(); j++ {
+				names = append(names, "."+inner.Field(j).Name)
+			}
+			line += ": " + strings.Join(names, " ")
+		}
+		lines = append(lines, line)
+	}
+	return "Available fields:\n" + strings.Join(lines, "\n") +
+		"\nHelpers: round <places> <value>, temp, speed, precip, icon, describe, clock, compass"
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// waybarOutput is the JSON a Waybar custom module with "return-type": "json" expects
+type waybarOutput struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip,omitempty"`
+	Class   string `json:"class,omitempty"`
+	// Percentage selects among the module's format-icons; sol uses the chance of precipitation
+	Percentage *int `json:"percentage,omitempty"`
+}
+
+// waybarFailure keeps the module visible when the forecast could not be fetched
+func waybarFailure(err error) waybarOutput {
+	return waybarOutput{Text: "⚠", Tooltip: err.Error(), Class: "error"}
+}
+
+// renderWaybar builds the module output: the current conditions as text and a
+// summary of the coming days as tooltip, with a class named after the weather
+func renderWaybar(label string, report Report, opts renderOptions) waybarOutput {
+	current := report.Current
+	icon := current.WeatherCode.Emoji()
+	if opts.NoEmoji {
+		icon = current.WeatherCode.String()
+	}
+
+	lines := []string{
+		label,
+		fmt.Sprintf("Now: %s, %.1f%s, wind %.1f %s%s", current.WeatherCode, current.Temperature,
+			report.Units.Temperature, current.WindSpeed, report.Units.WindSpeed, pressure(current)),
+	}
+	for _, day := range report.Daily {
+		lines = append(lines, fmt.Sprintf("%s: %s, %s to %s%s, %s %s (%s%%)",
+			day.Date, opts.condition(day.WeatherCode, snowDominant(day.SnowfallSum, day.PrecipitationSum, report.Units)),
+			formatValue(day.TemperatureMin), formatValue(day.TemperatureMax), report.Units.Temperature,
+			formatValue(day.PrecipitationSum), report.Units.Precipitation,
+			formatValue(day.PrecipitationProbabilityMax)))
+	}
+
+	output := waybarOutput{
+		Text:    fmt.Sprintf("%s %.0f%s", icon, current.Temperature, report.Units.Temperature),
+		Tooltip: strings.Join(lines, "\n"),
+		Class:   current.WeatherCode.Category(),
+	}
+	if len(report.Hourly) > 0 && report.Hourly[0].PrecipitationProbability != nil {
+		percentage := int(*report.Hourly[0].PrecipitationProbability)
+		output.Percentage = &percentage
+	}
+	return output
+}
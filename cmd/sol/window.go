@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// bestWindowHorizon is how many hours from the current one -best-window looks at
+const bestWindowHorizon = 48
+
+// parseComfortBand reads a -comfort value like "18,24"
+func parseComfortBand(value string) (weather.ComfortBand, error) {
+	low, high, ok := strings.Cut(value, ",")
+	if !ok {
+		return weather.ComfortBand{}, fmt.Errorf("comfort band %q must be in the form min,max", value)
+	}
+	var band weather.ComfortBand
+	var err error
+	if band.Min, err = strconv.ParseFloat(strings.TrimSpace(low), 64); err != nil {
+		return weather.ComfortBand{}, fmt.Errorf("invalid minimum in comfort band %q: %w", value, err)
+	}
+	if band.Max, err = strconv.ParseFloat(strings.TrimSpace(high), 64); err != nil {
+		return weather.ComfortBand{}, fmt.Errorf("invalid maximum in comfort band %q: %w", value, err)
+	}
+	if band.Min > band.Max {
+		return weather.ComfortBand{}, fmt.Errorf("comfort band %q has its minimum above its maximum", value)
+	}
+	return band, nil
+}
+
+// outdoorSlot passes the values of an hour that weather.OutdoorScore weighs
+func outdoorSlot(hour HourlyEntry) weather.HourlySlot {
+	return weather.HourlySlot{
+		Time:                     hour.Time,
+		Temperature:              hour.Temperature,
+		Precipitation:            hour.Precipitation,
+		PrecipitationProbability: hour.PrecipitationProbability,
+		WindSpeed:                hour.WindSpeed,
+		IsDay:                    hour.IsDay,
+	}
+}
+
+// OutdoorWindow is the stretch of hours -best-window recommends
+type OutdoorWindow struct {
+	// Start is the first hour and End the hour after the last
+	Start string  `json:"start"`
+	End   string  `json:"end"`
+	Score float64 `json:"score"`
+	// The highest precipitation probability, mean temperature and highest wind speed
+	PrecipitationProbability *float64 `json:"precipitation_probability"`
+	Temperature              *float64 `json:"temperature"`
+	WindSpeed                *float64 `json:"wind_speed"`
+}
+
+// findBestWindow returns the span of size consecutive hours with the lowest total
+// weather.OutdoorScore, the earliest one on ties. units is "metric" or "imperial".
+// With daylightOnly every hour of it has to be in daylight. It returns nil when no
+// span fits.
+func findBestWindow(hours []HourlyEntry, size int, band weather.ComfortBand, units string, daylightOnly bool) *OutdoorWindow {
+	best, bestScore := -1, 0.0
+	for start := 0; start+size <= len(hours); start++ {
+		score, fits := 0.0, true
+		for _, hour := range hours[start : start+size] {
+			if daylightOnly && !hour.IsDay {
+				fits = false
+				break
+			}
+			score += weather.OutdoorScore(outdoorSlot(hour), band, units)
+		}
+		if fits && (best < 0 || score < bestScore) {
+			best, bestScore = start, score
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+
+	span := hours[best : best+size]
+	window := &OutdoorWindow{Start: span[0].Time, End: span[size-1].Time, Score: bestScore}
+	if last, err := time.Parse("2006-01-02T15:04", window.End); err == nil {
+		window.End = last.Add(time.Hour).Format("2006-01-02T15:04")
+	}
+	var probabilities, temperatures, winds []float64
+	for _, hour := range span {
+		if hour.PrecipitationProbability != nil {
+			probabilities = append(probabilities, *hour.PrecipitationProbability)
+		}
+		if hour.Temperature != nil {
+			temperatures = append(temperatures, *hour.Temperature)
+		}
+		if hour.WindSpeed != nil {
+			winds = append(winds, *hour.WindSpeed)
+		}
+	}
+	if len(probabilities) > 0 {
+		highest := slices.Max(probabilities)
+		window.PrecipitationProbability = &highest
+	}
+	if len(temperatures) > 0 {
+		sum := 0.0
+		for _, t := range temperatures {
+			sum += t
+		}
+		mean := sum / float64(len(temperatures))
+		window.Temperature = &mean
+	}
+	if len(winds) > 0 {
+		highest := slices.Max(winds)
+		window.WindSpeed = &highest
+	}
+	return window
+}
+
+// markBestWindow picks the best window of size hours from the upcoming hours
+func (r *Report) markBestWindow(size int, band weather.ComfortBand, units string, daylightOnly bool) {
+	r.bestWindowHours = size
+	r.BestWindow = findBestWindow(r.upcoming, size, band, units, daylightOnly)
+}
+
+// windowSentence describes the best window like
+// "Best 2h window for outdoor plans: Tomorrow 10:00–12:00 (dry, 19°C, light wind)"
+func (opts renderOptions) windowSentence(report Report) string {
+	lead := fmt.Sprintf(opts.tr("Best %dh window for outdoor plans")+": ", report.bestWindowHours)
+	window := report.BestWindow
+	if window == nil {
+		return lead + fmt.Sprintf(opts.tr("none in the next %d hours"), bestWindowHorizon)
+	}
+
+	date, _, _ := strings.Cut(window.Start, "T")
+	var details []string
+	switch p := window.PrecipitationProbability; {
+	case p == nil:
+	case *p < 20:
+		details = append(details, opts.tr("dry"))
+	default:
+		details = append(details, fmt.Sprintf(opts.tr("up to %.0f%% chance of precipitation"), *p))
+	}
+	if window.Temperature != nil {
+		details = append(details, fmt.Sprintf("%.0f%s", *window.Temperature, report.Units.Temperature))
+	}
+	if window.WindSpeed != nil {
+		wind := *window.WindSpeed
+		if report.Units.WindSpeed == "mph" {
+			wind *= 1.609344
+		}
+		switch {
+		case wind < 20:
+			details = append(details, opts.tr("light wind"))
+		case wind < 40:
+			details = append(details, opts.tr("breezy"))
+		default:
+			details = append(details, opts.tr("windy"))
+		}
+	}
+
+	text := lead + fmt.Sprintf("%s %s–%s", opts.dayLabel(date, report.Current.Time, 0),
+		opts.clock(window.Start), opts.clock(window.End))
+	if len(details) > 0 {
+		text += " (" + strings.Join(details, ", ") + ")"
+	}
+	return text
+}
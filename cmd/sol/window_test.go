@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1eemur/sol/weather"
+)
+
+// outdoorHours returns comfortable, calm daylight hours from start with the
+// given precipitation probabilities
+func outdoorHours(t *testing.T, start string, probabilities ...float64) []HourlyEntry {
+	t.Helper()
+	at, err := time.Parse("2006-01-02T15:04", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hours := make([]HourlyEntry, len(probabilities))
+	for i := range hours {
+		hours[i] = HourlyEntry{
+			Time:                     at.Add(time.Duration(i) * time.Hour).Format("2006-01-02T15:04"),
+			PrecipitationProbability: &probabilities[i],
+			Temperature:              ptr(20),
+			WindSpeed:                ptr(10),
+			IsDay:                    true,
+		}
+	}
+	return hours
+}
+
+func TestFindBestWindow(t *testing.T) {
+	band := weather.DefaultComfortBand("metric")
+	night := outdoorHours(t, "2026-06-01T00:00", 0, 0, 50, 50, 80)
+	night[0].IsDay, night[1].IsDay = false, false
+	tests := []struct {
+		name         string
+		hours        []HourlyEntry
+		size         int
+		daylightOnly bool
+		start, end   string
+		score        float64
+	}{
+		{"driest span", outdoorHours(t, "2026-06-01T08:00", 50, 40, 0, 0, 30, 60), 2, false, "2026-06-01T10:00", "2026-06-01T12:00", 0},
+		{"lowest total, not lowest hour", outdoorHours(t, "2026-06-01T08:00", 0, 90, 0, 40, 40), 2, false, "2026-06-01T10:00", "2026-06-01T12:00", 40},
+		{"earliest of equal spans", outdoorHours(t, "2026-06-01T08:00", 10, 0, 0, 10, 0, 0, 10), 2, false, "2026-06-01T09:00", "2026-06-01T11:00", 0},
+		{"across midnight", outdoorHours(t, "2026-06-01T22:00", 60, 0, 0, 60), 2, false, "2026-06-01T23:00", "2026-06-02T01:00", 0},
+		{"whole forecast", outdoorHours(t, "2026-06-01T08:00", 10, 20), 2, false, "2026-06-01T08:00", "2026-06-01T10:00", 30},
+		{"night hours allowed", night, 2, false, "2026-06-01T00:00", "2026-06-01T02:00", 0},
+		{"daylight only", night, 2, true, "2026-06-01T02:00", "2026-06-01T04:00", 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findBestWindow(tt.hours, tt.size, band, "metric", tt.daylightOnly)
+			if got == nil {
+				t.Fatal("findBestWindow found no window")
+			}
+			if got.Start != tt.start || got.End != tt.end || got.Score != tt.score {
+				t.Errorf("got %s to %s scoring %g, want %s to %s scoring %g", got.Start, got.End, got.Score, tt.start, tt.end, tt.score)
+			}
+		})
+	}
+}
+
+func TestFindBestWindowSummary(t *testing.T) {
+	hours := outdoorHours(t, "2026-06-01T12:00", 5, 15, 10)
+	hours[0].Temperature, hours[1].Temperature, hours[2].Temperature = ptr(19), ptr(22), ptr(22)
+	hours[1].WindSpeed = ptr(14)
+	hours[2].WindSpeed = nil
+	got := findBestWindow(hours, 3, weather.DefaultComfortBand("metric"), "metric", false)
+	if got == nil {
+		t.Fatal("findBestWindow found no window")
+	}
+	if *got.PrecipitationProbability != 15 || *got.Temperature != 21 || *got.WindSpeed != 14 {
+		t.Errorf("got up to %g%%, %g°C on average and wind up to %g, want 15%%, 21°C and 14",
+			*got.PrecipitationProbability, *got.Temperature, *got.WindSpeed)
+	}
+}
+
+func TestFindBestWindowNone(t *testing.T) {
+	band := weather.DefaultComfortBand("metric")
+	if got := findBestWindow(outdoorHours(t, "2026-06-01T08:00", 0, 0), 3, band, "metric", false); got != nil {
+		t.Errorf("a 3h window in 2 hours: %+v", got)
+	}
+	dark := outdoorHours(t, "2026-06-01T22:00", 0, 0, 0)
+	dark[1].IsDay = false
+	if got := findBestWindow(dark, 2, band, "metric", true); got != nil {
+		t.Errorf("a 2h daylight window with a night hour in between: %+v", got)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/1eemur/sol
+
+go 1.22
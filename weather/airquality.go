@@ -0,0 +1,127 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// AirQualityResponse is the JSON returned by the air quality endpoint.
+// Values are pointers because the models leave gaps as null.
+type AirQualityResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+	Current   struct {
+		Time string `json:"time"`
+		// Particulate matter in µg/m³
+		PM25        *float64 `json:"pm2_5"`
+		PM10        *float64 `json:"pm10"`
+		Ozone       *float64 `json:"ozone"`
+		EuropeanAQI *float64 `json:"european_aqi"`
+		USAQI       *float64 `json:"us_aqi"`
+	} `json:"current"`
+	Hourly struct {
+		Time        []string   `json:"time"`
+		PM25        []*float64 `json:"pm2_5"`
+		PM10        []*float64 `json:"pm10"`
+		Ozone       []*float64 `json:"ozone"`
+		EuropeanAQI []*float64 `json:"european_aqi"`
+		USAQI       []*float64 `json:"us_aqi"`
+	} `json:"hourly"`
+}
+
+// AirQualitySlot is one hour of air quality; values are nil where the models have gaps
+type AirQualitySlot struct {
+	// Time is the local ISO time as the API gives it
+	Time        string
+	PM25        *float64
+	PM10        *float64
+	Ozone       *float64
+	EuropeanAQI *float64
+	USAQI       *float64
+}
+
+// HourlySlots returns every hour of the air quality forecast, lining the hourly series up by index
+func (r *AirQualityResponse) HourlySlots() []AirQualitySlot {
+	h := r.Hourly
+	slots := make([]AirQualitySlot, len(h.Time))
+	for i, timeStr := range h.Time {
+		slots[i] = AirQualitySlot{
+			Time:        timeStr,
+			PM25:        nullableAt(h.PM25, i),
+			PM10:        nullableAt(h.PM10, i),
+			Ozone:       nullableAt(h.Ozone, i),
+			EuropeanAQI: nullableAt(h.EuropeanAQI, i),
+			USAQI:       nullableAt(h.USAQI, i),
+		}
+	}
+	return slots
+}
+
+// AirQuality fetches the current and hourly particulate matter, ozone and the
+// European and US AQI for the coordinates from the Open-Meteo air quality API.
+// It shares the retries and the disk cache of Forecast.
+func (c *Client) AirQuality(ctx context.Context, latitude, longitude float64) (*AirQualityResponse, error) {
+	if err := ValidateCoordinates(latitude, longitude); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(latitude, 'f', -1, 64))
+	params.Add("longitude", strconv.FormatFloat(longitude, 'f', -1, 64))
+	params.Add("current", "pm2_5,pm10,ozone,european_aqi,us_aqi")
+	params.Add("hourly", "pm2_5,pm10,ozone,european_aqi,us_aqi")
+	params.Add("timezone", "auto")
+
+	var airQuality AirQualityResponse
+	_, err := c.fetchCached(ctx, c.AirQualityURL, params, func(body []byte) error {
+		airQuality = AirQualityResponse{}
+		if err := json.Unmarshal(body, &airQuality); err != nil {
+			return &DecodeError{What: "air quality response", Err: err}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("air quality request failed: %w", err)
+	}
+	return &airQuality, nil
+}
+
+// EuropeanAQICategory names the band of a European Air Quality Index value
+func EuropeanAQICategory(index float64) string {
+	switch {
+	case index <= 20:
+		return "Good"
+	case index <= 40:
+		return "Fair"
+	case index <= 60:
+		return "Moderate"
+	case index <= 80:
+		return "Poor"
+	case index <= 100:
+		return "Very poor"
+	default:
+		return "Extremely poor"
+	}
+}
+
+// USAQICategory names the band of a US Air Quality Index value
+func USAQICategory(index float64) string {
+	switch {
+	case index <= 50:
+		return "Good"
+	case index <= 100:
+		return "Moderate"
+	case index <= 150:
+		return "Unhealthy for sensitive groups"
+	case index <= 200:
+		return "Unhealthy"
+	case index <= 300:
+		return "Very unhealthy"
+	default:
+		return "Hazardous"
+	}
+}
@@ -0,0 +1,53 @@
+package weather
+
+import "testing"
+
+func TestEuropeanAQICategory(t *testing.T) {
+	tests := []struct {
+		index float64
+		want  string
+	}{
+		{0, "Good"},
+		{20, "Good"},
+		{20.5, "Fair"},
+		{40, "Fair"},
+		{41, "Moderate"},
+		{60, "Moderate"},
+		{61, "Poor"},
+		{80, "Poor"},
+		{81, "Very poor"},
+		{100, "Very poor"},
+		{101, "Extremely poor"},
+		{350, "Extremely poor"},
+	}
+	for _, tt := range tests {
+		if got := EuropeanAQICategory(tt.index); got != tt.want {
+			t.Errorf("EuropeanAQICategory(%g) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestUSAQICategory(t *testing.T) {
+	tests := []struct {
+		index float64
+		want  string
+	}{
+		{0, "Good"},
+		{50, "Good"},
+		{51, "Moderate"},
+		{100, "Moderate"},
+		{101, "Unhealthy for sensitive groups"},
+		{150, "Unhealthy for sensitive groups"},
+		{151, "Unhealthy"},
+		{200, "Unhealthy"},
+		{201, "Very unhealthy"},
+		{300, "Very unhealthy"},
+		{301, "Hazardous"},
+		{500, "Hazardous"},
+	}
+	for _, tt := range tests {
+		if got := USAQICategory(tt.index); got != tt.want {
+			t.Errorf("USAQICategory(%g) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
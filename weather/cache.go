@@ -0,0 +1,168 @@
+package weather
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCacheBytes caps the total size of the cache directory
+const maxCacheBytes = 10 << 20
+
+// Cache stores raw API responses on disk so repeated runs skip the network
+type Cache struct {
+	Dir string
+	// TTL is how long an entry is used without asking the API
+	TTL time.Duration
+	// MaxStale is how old an entry may be and still stand in for a failed request;
+	// older entries are removed
+	MaxStale time.Duration
+}
+
+type cacheEntry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Response  json.RawMessage `json:"response"`
+}
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/sol, falling back to ~/.cache/sol
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "sol"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding home directory: %w", err)
+	}
+	return filepath.Join(home, ".cache", "sol"), nil
+}
+
+// cacheKey names the entry for a request. The coordinates are rounded to about
+// 1 km so nearby lookups share an entry, and the endpoint and other parameters
+// are hashed so a different API, unit system or variable list never reuses the wrong data.
+func cacheKey(endpoint string, params url.Values) string {
+	latitude, _ := strconv.ParseFloat(params.Get("latitude"), 64)
+	longitude, _ := strconv.ParseFloat(params.Get("longitude"), 64)
+	rest := url.Values{}
+	for name, values := range params {
+		if name != "latitude" && name != "longitude" {
+			rest[name] = values
+		}
+	}
+	sum := sha256.Sum256([]byte(endpoint + "?" + rest.Encode()))
+	return fmt.Sprintf("%.2f_%.2f_%x.json", latitude, longitude, sum[:6])
+}
+
+// load returns the cached response body for key together with its age, provided it
+// is no older than maxAge
+func (c *Cache) load(key string, maxAge time.Duration) ([]byte, time.Duration, bool) {
+	data, err := os.ReadFile(filepath.Join(c.Dir, key))
+	if err != nil {
+		return nil, 0, false
+	}
+
+	// A corrupt entry is treated as a miss so the caller fetches live data
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, 0, false
+	}
+	age := time.Since(entry.FetchedAt)
+	if age > maxAge {
+		return nil, 0, false
+	}
+
+	return entry.Response, age, true
+}
+
+// store saves the raw response body under key and prunes old entries
+func (c *Cache) store(key string, body []byte) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cacheEntry{FetchedAt: time.Now(), Response: body})
+	if err != nil {
+		return fmt.Errorf("error encoding cache entry: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(c.Dir, key), data, 0o644); err != nil {
+		return fmt.Errorf("error writing cache entry: %w", err)
+	}
+
+	c.prune()
+	return nil
+}
+
+// prune removes entries older than MaxStale, then the oldest entries until the
+// directory fits in maxCacheBytes
+func (c *Cache) prune() {
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return
+	}
+
+	type cachedFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cachedFile
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(c.Dir, entry.Name())
+		if time.Since(info.ModTime()) > c.MaxStale {
+			os.Remove(path)
+			continue
+		}
+		files = append(files, cachedFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, file := range files {
+		if total <= maxCacheBytes {
+			break
+		}
+		if os.Remove(file.path) == nil {
+			total -= file.size
+		}
+	}
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path,
+// so readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,241 @@
+// Package weather fetches and decodes forecasts from the Open-Meteo API.
+//
+// A Client holds the HTTP client, endpoints and retry policy:
+//
+//	client := weather.NewClient()
+//	forecast, err := client.Forecast(ctx, weather.Options{Latitude: 52.52, Longitude: 13.41, Days: 3})
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Diagnostics receives warnings such as falling back to a stale cached response.
+// It is discarded unless a program sets it, for example to os.Stderr.
+var Diagnostics io.Writer = io.Discard
+
+// Verbose receives progress details such as retries, cache hits and the hourly
+// slot taken as current. It is discarded unless a program sets it.
+var Verbose io.Writer = io.Discard
+
+// logf writes one line of informational output to Diagnostics
+func logf(format string, args ...any) {
+	fmt.Fprintf(Diagnostics, format+"\n", args...)
+}
+
+// debugf writes one line of progress output to Verbose
+func debugf(format string, args ...any) {
+	fmt.Fprintf(Verbose, format+"\n", args...)
+}
+
+// Client talks to the Open-Meteo forecast and geocoding APIs
+type Client struct {
+	// HTTP sends the requests; its timeout applies to each attempt.
+	// Replace it, or just its Transport with any http.RoundTripper, to add
+	// a proxy or to talk to an httptest.Server.
+	HTTP *http.Client
+	// BaseURL is the forecast endpoint; it can be pointed at a mock server or proxy
+	BaseURL string
+	// GeocodingURL is the place name search endpoint
+	GeocodingURL string
+	// AirQualityURL is the air quality endpoint
+	AirQualityURL string
+	// MarineURL is the sea state endpoint
+	MarineURL string
+	// ArchiveURL is the historical weather endpoint used for Options.Archive
+	ArchiveURL string
+	// EnsembleURL is the ensemble forecast endpoint
+	EnsembleURL string
+	// UserAgent identifies the program to the API; set it to name your own program
+	UserAgent string
+	// APIKey is sent as the apikey parameter of every request; see UseAPIKey
+	APIKey string
+	// Retries is how many times a failed request is retried
+	Retries int
+	// Cache stores responses on disk; nil disables caching
+	Cache *Cache
+}
+
+// sharedTransport is used by every client from NewClient, so requests reuse
+// kept-alive connections across locations, extras and -watch refreshes
+var sharedTransport = newTransport()
+
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Several locations and extras go to the same hosts at once
+	transport.MaxIdleConnsPerHost = 8
+	return transport
+}
+
+// NewClient returns a client for the public Open-Meteo API
+func NewClient() *Client {
+	return &Client{
+		HTTP:          &http.Client{Timeout: 10 * time.Second, Transport: sharedTransport},
+		BaseURL:       "https://api.open-meteo.com/v1/forecast",
+		GeocodingURL:  "https://geocoding-api.open-meteo.com/v1/search",
+		AirQualityURL: "https://air-quality-api.open-meteo.com/v1/air-quality",
+		MarineURL:     "https://marine-api.open-meteo.com/v1/marine",
+		ArchiveURL:    "https://archive-api.open-meteo.com/v1/archive",
+		EnsembleURL:   "https://ensemble-api.open-meteo.com/v1/ensemble",
+		UserAgent:     "sol",
+		Retries:       3,
+	}
+}
+
+// Options selects the place and span of a forecast
+type Options struct {
+	Latitude  float64
+	Longitude float64
+	// Units is "metric" (the default) or "imperial" for °F, mph and inches
+	Units string
+	// Days is the number of forecast days; 0 leaves it up to the API default
+	Days int
+	// PastDays adds up to MaxPastDays days of history before today
+	PastDays int
+	// StartDate and EndDate (YYYY-MM-DD) select a span of days instead of Days and PastDays
+	StartDate string
+	EndDate   string
+	// Archive fetches StartDate to EndDate from the historical archive, which has
+	// no current conditions, precipitation probabilities or UV index
+	Archive bool
+	// NoDaily leaves out the daily series for callers that only show the hours
+	NoDaily bool
+	// Model picks one of Models instead of the API's best match
+	Model string
+}
+
+// MaxPastDays is the most history Open-Meteo returns with a forecast
+const MaxPastDays = 92
+
+// ValidateCoordinates checks that latitude is within [-90, 90] and longitude within [-180, 180]
+func ValidateCoordinates(latitude, longitude float64) error {
+	if math.IsNaN(latitude) || latitude < -90 || latitude > 90 {
+		return fmt.Errorf("latitude %g is out of range, it must be between -90 and 90", latitude)
+	}
+	if math.IsNaN(longitude) || longitude < -180 || longitude > 180 {
+		return fmt.Errorf("longitude %g is out of range, it must be between -180 and 180", longitude)
+	}
+	return nil
+}
+
+// Forecast fetches the forecast, giving up when ctx is cancelled or its deadline passes.
+// When the API cannot be reached, a stale cached response is returned if one is young
+// enough; its CacheAge is then set. With opts.Archive the days come from the archive.
+func (c *Client) Forecast(ctx context.Context, opts Options) (*WeatherResponse, error) {
+	endpoint, params, err := c.forecastRequest(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the JSON response, fresh or cached
+	var weatherResponse WeatherResponse
+	age, err := c.fetchCached(ctx, endpoint, params, func(body []byte) error {
+		weatherResponse = WeatherResponse{}
+		if err := json.Unmarshal(body, &weatherResponse); err != nil {
+			return &DecodeError{What: "JSON response", Err: err}
+		}
+		weatherResponse.Unavailable = dropNullSeries(body, &weatherResponse)
+		// Points over open water can come back without a timezone; the times are then UTC
+		if weatherResponse.Timezone == "" {
+			logf("Warning: the API returned no timezone, using UTC")
+			weatherResponse.Timezone = "UTC"
+		}
+		// A series that does not cover its times reads as n/a where it has no values
+		weatherResponse.Incomplete = weatherResponse.MatchLengths(params)
+		if len(weatherResponse.Incomplete) > 0 {
+			logf("Warning: the API returned incomplete series: %s", strings.Join(weatherResponse.Incomplete, ", "))
+		}
+		if err := weatherResponse.ParseTimes(); err != nil {
+			return &DecodeError{What: "JSON response", Err: err}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	weatherResponse.CacheAge = age
+	return &weatherResponse, nil
+}
+
+// GetWeatherForecast fetches the forecast with a default client and the API's
+// default units and span. It predates Client and is kept for existing callers.
+func GetWeatherForecast(latitude float64, longitude float64) (*WeatherResponse, error) {
+	return GetWeatherForecastContext(context.Background(), latitude, longitude, "", 0)
+}
+
+// GetWeatherForecastContext fetches the forecast with a default client, giving up
+// when ctx is cancelled or its deadline passes. New code should use Client.Forecast.
+func GetWeatherForecastContext(ctx context.Context, latitude float64, longitude float64, units string, forecastDays int) (*WeatherResponse, error) {
+	return NewClient().Forecast(ctx, Options{Latitude: latitude, Longitude: longitude, Units: units, Days: forecastDays})
+}
+
+// ForecastURL returns the URL Forecast would request for opts, with any API key masked
+func (c *Client) ForecastURL(opts Options) (string, error) {
+	endpoint, params, err := c.forecastRequest(opts)
+	if err != nil {
+		return "", err
+	}
+	return MaskAPIKey(c.requestURL(endpoint, params)), nil
+}
+
+// forecastRequest picks the endpoint and builds the query parameters for opts
+func (c *Client) forecastRequest(opts Options) (string, url.Values, error) {
+	if err := ValidateCoordinates(opts.Latitude, opts.Longitude); err != nil {
+		return "", nil, err
+	}
+
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(opts.Latitude, 'f', -1, 64))
+	params.Add("longitude", strconv.FormatFloat(opts.Longitude, 'f', -1, 64))
+	endpoint, daily := c.BaseURL, ""
+	if opts.Archive {
+		if opts.StartDate == "" || opts.EndDate == "" {
+			return "", nil, fmt.Errorf("archive requests need a start and end date")
+		}
+		endpoint = c.ArchiveURL
+		params.Add("hourly", "temperature_2m,apparent_temperature,precipitation,weather_code,relative_humidity_2m,dew_point_2m,wind_speed_10m,wind_direction_10m,wind_gusts_10m,is_day,snowfall,snow_depth,cloud_cover,cloud_cover_low,cloud_cover_mid,cloud_cover_high,pressure_msl,surface_pressure")
+		daily = "temperature_2m_max,temperature_2m_min,apparent_temperature_max,apparent_temperature_min,precipitation_sum,rain_sum,precipitation_hours,snowfall_sum,wind_speed_10m_max,wind_gusts_10m_max,wind_direction_10m_dominant,sunrise,sunset,daylight_duration,weather_code"
+	} else {
+		params.Add("current", "temperature_2m,apparent_temperature,weather_code,wind_speed_10m,wind_direction_10m,relative_humidity_2m,is_day,pressure_msl")
+		params.Add("hourly", "temperature_2m,apparent_temperature,precipitation_probability,precipitation,weather_code,relative_humidity_2m,dew_point_2m,wind_speed_10m,wind_direction_10m,wind_gusts_10m,uv_index,is_day,snowfall,snow_depth,cloud_cover,cloud_cover_low,cloud_cover_mid,cloud_cover_high,pressure_msl,surface_pressure")
+		daily = "temperature_2m_max,temperature_2m_min,apparent_temperature_max,apparent_temperature_min,precipitation_sum,rain_sum,precipitation_hours,snowfall_sum,precipitation_probability_max,precipitation_probability_mean,wind_speed_10m_max,wind_gusts_10m_max,wind_direction_10m_dominant,sunrise,sunset,daylight_duration,uv_index_max,weather_code"
+	}
+	if !opts.NoDaily {
+		params.Add("daily", daily)
+	}
+	if opts.Model != "" {
+		params.Add("models", opts.Model)
+	}
+	params.Add("timezone", "auto")
+	addSpan(params, opts)
+	if opts.Units == "imperial" {
+		params.Add("temperature_unit", "fahrenheit")
+		params.Add("wind_speed_unit", "mph")
+		params.Add("precipitation_unit", "inch")
+	}
+	return endpoint, params, nil
+}
+
+// addSpan selects the days of opts: a date span, or a number of days around today
+func addSpan(params url.Values, opts Options) {
+	if opts.StartDate != "" {
+		params.Add("start_date", opts.StartDate)
+		params.Add("end_date", opts.EndDate)
+		return
+	}
+	if opts.Days > 0 {
+		params.Add("forecast_days", strconv.Itoa(opts.Days))
+	}
+	if opts.PastDays > 0 {
+		params.Add("past_days", strconv.Itoa(opts.PastDays))
+	}
+}
@@ -0,0 +1,463 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client whose endpoints all point at an httptest.Server
+// running handler, without retries so failures come back straight away
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c := NewClient()
+	c.HTTP = server.Client()
+	c.SetBaseURL(server.URL)
+	c.Retries = 0
+	return c
+}
+
+// serveBody answers every request with status and body
+func serveBody(status int, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}
+}
+
+// forecastBody returns Open-Meteo forecast JSON for Berlin with every series the
+// client requests, hours hourly values from 2026-06-01T00:00 and days daily values.
+// edit can change the hourly and daily series before they are encoded.
+func forecastBody(t *testing.T, hours, days int, edit func(hourly, daily map[string]any)) []byte {
+	t.Helper()
+	_, params, err := NewClient().forecastRequest(Options{Latitude: 52.52, Longitude: 13.41})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	series := func(names string, n int, at func(i int) string) map[string]any {
+		values := map[string]any{}
+		times := make([]string, n)
+		for i := range times {
+			times[i] = at(i)
+		}
+		values["time"] = times
+		for _, name := range strings.Split(names, ",") {
+			numbers := make([]float64, n)
+			for i := range numbers {
+				numbers[i] = float64(i % 10)
+			}
+			values[name] = numbers
+		}
+		return values
+	}
+	hourly := series(params.Get("hourly"), hours, func(i int) string {
+		return start.Add(time.Duration(i) * time.Hour).Format("2006-01-02T15:04")
+	})
+	daily := series(params.Get("daily"), days, func(i int) string {
+		return start.AddDate(0, 0, i).Format("2006-01-02")
+	})
+	sunrise, sunset := make([]string, days), make([]string, days)
+	for i, date := range daily["time"].([]string) {
+		sunrise[i], sunset[i] = date+"T04:43", date+"T21:29"
+	}
+	daily["sunrise"], daily["sunset"] = sunrise, sunset
+	if edit != nil {
+		edit(hourly, daily)
+	}
+	body, err := json.Marshal(map[string]any{
+		"latitude":           52.52,
+		"longitude":          13.41,
+		"timezone":           "Europe/Berlin",
+		"utc_offset_seconds": 7200,
+		"current":            map[string]any{"time": "2026-06-01T10:00", "temperature_2m": 21.5, "weather_code": 2},
+		"hourly":             hourly,
+		"daily":              daily,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestForecast(t *testing.T) {
+	full := forecastBody(t, 48, 2, nil)
+	tests := []struct {
+		name   string
+		status int
+		body   []byte
+		check  func(t *testing.T, r *WeatherResponse, err error)
+	}{
+		{
+			name:   "forecast",
+			status: http.StatusOK,
+			body:   full,
+			check: func(t *testing.T, r *WeatherResponse, err error) {
+				if err != nil {
+					t.Fatalf("Forecast: %v", err)
+				}
+				if r.Timezone != "Europe/Berlin" || r.Current.Temperature2m != 21.5 || r.Current.WeatherCode != 2 {
+					t.Errorf("got timezone %q, current %v°C and code %d", r.Timezone, r.Current.Temperature2m, r.Current.WeatherCode)
+				}
+				if len(r.Hourly.Time) != 48 || len(r.HourlyTimes) != 48 || len(r.Daily.Time) != 2 {
+					t.Errorf("got %d hours, %d parsed times and %d days, want 48, 48 and 2", len(r.Hourly.Time), len(r.HourlyTimes), len(r.Daily.Time))
+				}
+				if len(r.Incomplete) > 0 || len(r.Unavailable) > 0 {
+					t.Errorf("got incomplete series %v and unavailable %v, want none", r.Incomplete, r.Unavailable)
+				}
+				if got := r.HourlyTimes[10].Format(time.RFC3339); got != "2026-06-01T10:00:00+02:00" {
+					t.Errorf("hour 10 parsed as %s, want 2026-06-01T10:00:00+02:00", got)
+				}
+			},
+		},
+		{
+			name:   "API error",
+			status: http.StatusBadRequest,
+			body:   []byte(`{"error":true,"reason":"Latitude must be in range of -90 to 90°. Given: 91.0."}`),
+			check: func(t *testing.T, r *WeatherResponse, err error) {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("got %v (%T), want an *APIError", err, err)
+				}
+				if apiErr.StatusCode != http.StatusBadRequest || apiErr.Reason != "Latitude must be in range of -90 to 90°. Given: 91.0." {
+					t.Errorf("got status %d and reason %q", apiErr.StatusCode, apiErr.Reason)
+				}
+			},
+		},
+		{
+			name:   "truncated JSON",
+			status: http.StatusOK,
+			body:   full[:len(full)/2],
+			check: func(t *testing.T, r *WeatherResponse, err error) {
+				var decodeErr *DecodeError
+				if !errors.As(err, &decodeErr) {
+					t.Fatalf("got %v (%T), want a *DecodeError", err, err)
+				}
+			},
+		},
+		{
+			name:   "mismatched lengths",
+			status: http.StatusOK,
+			body: forecastBody(t, 48, 2, func(hourly, daily map[string]any) {
+				hourly["temperature_2m"] = hourly["temperature_2m"].([]float64)[:40]
+				daily["weather_code"] = []float64{1, 2, 3}
+			}),
+			check: func(t *testing.T, r *WeatherResponse, err error) {
+				if err != nil {
+					t.Fatalf("Forecast: %v", err)
+				}
+				if !slices.Equal(r.Incomplete, []string{"temperature_2m"}) {
+					t.Errorf("Incomplete = %v, want [temperature_2m]", r.Incomplete)
+				}
+				if len(r.Daily.WeatherCode) != 2 {
+					t.Errorf("got %d daily weather codes, want them cut to the 2 days", len(r.Daily.WeatherCode))
+				}
+				slots := r.HourlySlots()
+				if slots[39].Temperature == nil || slots[40].Temperature != nil {
+					t.Errorf("hours 39 and 40 have temperatures %v and %v, want a value and n/a", slots[39].Temperature, slots[40].Temperature)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, serveBody(tt.status, tt.body))
+			r, err := c.Forecast(context.Background(), Options{Latitude: 52.52, Longitude: 13.41})
+			tt.check(t, r, err)
+		})
+	}
+}
+
+func TestForecastHourIndex(t *testing.T) {
+	c := newTestClient(t, serveBody(http.StatusOK, forecastBody(t, 24, 1, nil)))
+	r, err := c.Forecast(context.Background(), Options{Latitude: 52.52, Longitude: 13.41})
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	tests := []struct {
+		now          string
+		fromNextHour bool
+		want         int
+	}{
+		{"2026-06-01T08:20:00Z", false, 10},
+		{"2026-06-01T08:20:00Z", true, 11},
+		{"2026-05-31T12:00:00Z", false, 0},
+		{"2026-06-02T12:00:00Z", false, 0},
+	}
+	for _, tt := range tests {
+		now, err := time.Parse(time.RFC3339, tt.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := r.HourIndexAt(now, tt.fromNextHour)
+		if err != nil {
+			t.Fatalf("HourIndexAt: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("HourIndexAt(%s, %v) = %d, want %d", tt.now, tt.fromNextHour, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCoordinates(t *testing.T) {
+	tests := []struct {
+		latitude, longitude float64
+		valid               bool
+	}{
+		{0, 0, true},
+		{90, 180, true},
+		{-90, -180, true},
+		{90.0001, 0, false},
+		{-90.0001, 0, false},
+		{0, 180.0001, false},
+		{0, -180.0001, false},
+		{math.NaN(), 0, false},
+		{0, math.NaN(), false},
+		{math.Inf(1), 0, false},
+	}
+	for _, tt := range tests {
+		err := ValidateCoordinates(tt.latitude, tt.longitude)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("ValidateCoordinates(%g, %g) = %v, want valid %v", tt.latitude, tt.longitude, err, tt.valid)
+		}
+	}
+}
+
+func TestClientsShareConnections(t *testing.T) {
+	var mu sync.Mutex
+	opened := 0
+	server := httptest.NewUnstartedServer(serveBody(http.StatusOK, forecastBody(t, 24, 1, nil)))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			opened++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	// Each request gets a new client, as every location and -watch refresh does
+	for i := 0; i < 3; i++ {
+		c := NewClient()
+		c.SetBaseURL(server.URL)
+		if _, err := c.Forecast(context.Background(), Options{Latitude: 52.52, Longitude: 13.41}); err != nil {
+			t.Fatalf("Forecast %d: %v", i+1, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if opened != 1 {
+		t.Errorf("three requests from new clients opened %d connections, want 1", opened)
+	}
+}
+
+func TestForecastWithoutTimezone(t *testing.T) {
+	var diagnostics strings.Builder
+	Diagnostics = &diagnostics
+	t.Cleanup(func() { Diagnostics = io.Discard })
+
+	// Points over open water can come back with an empty timezone
+	var body map[string]any
+	if err := json.Unmarshal(forecastBody(t, 24, 1, nil), &body); err != nil {
+		t.Fatal(err)
+	}
+	body["timezone"], body["utc_offset_seconds"] = "", 0
+	withoutZone, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestClient(t, serveBody(http.StatusOK, withoutZone))
+	r, err := c.Forecast(context.Background(), Options{Latitude: 0, Longitude: -30})
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	if r.Timezone != "UTC" {
+		t.Errorf("Timezone = %q, want UTC", r.Timezone)
+	}
+	if got, err := r.HourIndexAt(time.Date(2026, 6, 1, 10, 30, 0, 0, time.UTC), false); err != nil || got != 10 {
+		t.Errorf("HourIndexAt(10:30 UTC) = %d, %v, want hour 10", got, err)
+	}
+	if !strings.Contains(diagnostics.String(), "no timezone") {
+		t.Errorf("no warning about the missing timezone in %q", diagnostics.String())
+	}
+	if strings.Contains(diagnostics.String(), "Could not load timezone") {
+		t.Errorf("the UTC fallback itself fell back: %q", diagnostics.String())
+	}
+}
+
+func TestForecastURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		apiKey   string
+		endpoint string
+		want     map[string]string
+		absent   []string
+	}{
+		{
+			name:     "defaults",
+			opts:     Options{Latitude: 52.52, Longitude: 13.41},
+			endpoint: "https://api.open-meteo.com/v1/forecast",
+			want:     map[string]string{"latitude": "52.52", "longitude": "13.41", "timezone": "auto"},
+			absent:   []string{"forecast_days", "past_days", "models", "temperature_unit", "wind_speed_unit", "precipitation_unit", "apikey"},
+		},
+		{
+			name:     "imperial with days",
+			opts:     Options{Latitude: -33.8688, Longitude: 151.2093, Units: "imperial", Days: 7, PastDays: 2},
+			endpoint: "https://api.open-meteo.com/v1/forecast",
+			want: map[string]string{
+				"latitude": "-33.8688", "longitude": "151.2093", "forecast_days": "7", "past_days": "2",
+				"temperature_unit": "fahrenheit", "wind_speed_unit": "mph", "precipitation_unit": "inch",
+			},
+		},
+		{
+			name:     "date span without daily series",
+			opts:     Options{Latitude: 1, Longitude: 2, Days: 7, StartDate: "2026-06-01", EndDate: "2026-06-03", NoDaily: true, Model: "ecmwf_ifs025"},
+			endpoint: "https://api.open-meteo.com/v1/forecast",
+			want:     map[string]string{"start_date": "2026-06-01", "end_date": "2026-06-03", "models": "ecmwf_ifs025"},
+			absent:   []string{"daily", "forecast_days"},
+		},
+		{
+			name:     "archive",
+			opts:     Options{Latitude: 1, Longitude: 2, StartDate: "1990-01-01", EndDate: "1990-01-31", Archive: true},
+			endpoint: "https://archive-api.open-meteo.com/v1/archive",
+			want:     map[string]string{"start_date": "1990-01-01", "end_date": "1990-01-31"},
+			absent:   []string{"current"},
+		},
+		{
+			name:     "masked API key",
+			opts:     Options{Latitude: 1, Longitude: 2},
+			apiKey:   "secret",
+			endpoint: "https://customer-api.open-meteo.com/v1/forecast",
+			want:     map[string]string{"apikey": "***"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient()
+			if tt.apiKey != "" {
+				c.UseAPIKey(tt.apiKey)
+			}
+			got, err := c.ForecastURL(tt.opts)
+			if err != nil {
+				t.Fatalf("ForecastURL: %v", err)
+			}
+			endpoint, query, _ := strings.Cut(got, "?")
+			if endpoint != tt.endpoint {
+				t.Errorf("endpoint is %s, want %s", endpoint, tt.endpoint)
+			}
+			params, err := url.ParseQuery(query)
+			if err != nil {
+				t.Fatalf("query %q: %v", query, err)
+			}
+			for name, want := range tt.want {
+				if value := params.Get(name); value != want {
+					t.Errorf("%s=%q, want %q", name, value, want)
+				}
+			}
+			for _, name := range tt.absent {
+				if params.Has(name) {
+					t.Errorf("%s=%q, want it left out", name, params.Get(name))
+				}
+			}
+			if strings.Contains(got, "secret") {
+				t.Errorf("the API key shows in %s", got)
+			}
+		})
+	}
+}
+
+func TestForecastURLErrors(t *testing.T) {
+	for _, opts := range []Options{
+		{Latitude: 91, Longitude: 0},
+		{Latitude: 0, Longitude: -181},
+		{Latitude: 1, Longitude: 2, Archive: true, StartDate: "1990-01-01"},
+	} {
+		if got, err := NewClient().ForecastURL(opts); err == nil {
+			t.Errorf("ForecastURL(%+v) = %s, want an error", opts, got)
+		}
+	}
+}
+
+func TestForecastWithoutProbabilities(t *testing.T) {
+	nulls := make([]any, 24)
+	tests := []struct {
+		name        string
+		edit        func(hourly, daily map[string]any)
+		incomplete  []string
+		unavailable []string
+		missingFrom int
+		// dailyMissing is set when the daily probabilities read as n/a too
+		dailyMissing bool
+	}{
+		{
+			name: "absent",
+			edit: func(hourly, daily map[string]any) {
+				delete(hourly, "precipitation_probability")
+				delete(daily, "precipitation_probability_max")
+			},
+			incomplete:   []string{"precipitation_probability", "precipitation_probability_max"},
+			missingFrom:  0,
+			dailyMissing: true,
+		},
+		{
+			name: "shorter than the times",
+			edit: func(hourly, daily map[string]any) {
+				hourly["precipitation_probability"] = hourly["precipitation_probability"].([]float64)[:6]
+			},
+			incomplete:  []string{"precipitation_probability"},
+			missingFrom: 6,
+		},
+		{
+			name: "null throughout",
+			edit: func(hourly, daily map[string]any) {
+				hourly["precipitation_probability"] = nulls
+			},
+			unavailable: []string{"precipitation_probability"},
+			missingFrom: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, serveBody(http.StatusOK, forecastBody(t, 24, 1, tt.edit)))
+			r, err := c.Forecast(context.Background(), Options{Latitude: 52.52, Longitude: 13.41})
+			if err != nil {
+				t.Fatalf("Forecast: %v", err)
+			}
+			if !slices.Equal(r.Incomplete, tt.incomplete) {
+				t.Errorf("Incomplete = %v, want %v", r.Incomplete, tt.incomplete)
+			}
+			if !slices.Equal(r.Unavailable, tt.unavailable) {
+				t.Errorf("Unavailable = %v, want %v", r.Unavailable, tt.unavailable)
+			}
+			for i, slot := range r.HourlySlots() {
+				if missing := slot.PrecipitationProbability == nil; missing != (i >= tt.missingFrom) {
+					t.Errorf("hour %d has probability %v, want n/a from hour %d", i, slot.PrecipitationProbability, tt.missingFrom)
+				}
+				if slot.Temperature == nil {
+					t.Errorf("hour %d lost its temperature", i)
+				}
+			}
+			if missing := r.DailySlots()[0].PrecipitationProbabilityMax == nil; missing != tt.dailyMissing {
+				t.Errorf("the day's highest probability is n/a: %v, want %v", missing, tt.dailyMissing)
+			}
+		})
+	}
+}
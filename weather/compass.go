@@ -0,0 +1,35 @@
+package weather
+
+import "math"
+
+var compassPoints = []string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// windArrows point where the wind blows to, for wind from N, NE, E, ... NW
+var windArrows = []string{"↓", "↙", "←", "↖", "↑", "↗", "→", "↘"}
+
+// DegreesToCompass turns a wind direction in degrees (where the wind comes from)
+// into a 16-point compass label followed by an arrow showing where it blows,
+// e.g. "NW ↘". Each point covers 22.5°, so N spans 348.75° to 11.25°.
+func DegreesToCompass(deg float64) string {
+	deg = normalizeDegrees(deg)
+	arrow := int(math.Floor(deg/45+0.5)) % len(windArrows)
+	return CompassPoint(deg) + " " + windArrows[arrow]
+}
+
+// CompassPoint is DegreesToCompass without the arrow, e.g. "NW"
+func CompassPoint(deg float64) string {
+	point := int(math.Floor(normalizeDegrees(deg)/22.5+0.5)) % len(compassPoints)
+	return compassPoints[point]
+}
+
+// normalizeDegrees brings deg into [0, 360)
+func normalizeDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
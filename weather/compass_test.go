@@ -0,0 +1,47 @@
+package weather
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDegreesToCompass(t *testing.T) {
+	tests := []struct {
+		deg  float64
+		want string
+	}{
+		{0, "N ↓"},
+		{22.5, "NNE ↙"},
+		{45, "NE ↙"},
+		{67.5, "ENE ←"},
+		{90, "E ←"},
+		{112.5, "ESE ↖"},
+		{135, "SE ↖"},
+		{157.5, "SSE ↑"},
+		{180, "S ↑"},
+		{202.5, "SSW ↗"},
+		{225, "SW ↗"},
+		{247.5, "WSW →"},
+		{270, "W →"},
+		{292.5, "WNW ↘"},
+		{315, "NW ↘"},
+		{337.5, "NNW ↓"},
+		// N spans 348.75° to 11.25°
+		{11.24, "N ↓"},
+		{11.25, "NNE ↓"},
+		{348.74, "NNW ↓"},
+		{348.75, "N ↓"},
+		{360, "N ↓"},
+		{765, "NE ↙"},
+		{-10, "N ↓"},
+		{-90, "W →"},
+		{-360, "N ↓"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.deg), func(t *testing.T) {
+			if got := DegreesToCompass(tt.deg); got != tt.want {
+				t.Errorf("DegreesToCompass(%g) = %q, want %q", tt.deg, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package weather
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// endpoints returns the address of every endpoint field of c with its usual path
+func (c *Client) endpoints() map[*string]string {
+	return map[*string]string{
+		&c.BaseURL:       "/v1/forecast",
+		&c.GeocodingURL:  "/v1/search",
+		&c.AirQualityURL: "/v1/air-quality",
+		&c.MarineURL:     "/v1/marine",
+		&c.ArchiveURL:    "/v1/archive",
+		&c.EnsembleURL:   "/v1/ensemble",
+	}
+}
+
+// SetBaseURL points every endpoint at one server, such as a self-hosted
+// Open-Meteo instance, keeping the usual paths: base+"/v1/forecast" and so on
+func (c *Client) SetBaseURL(base string) {
+	base = strings.TrimSuffix(base, "/")
+	for field, path := range c.endpoints() {
+		*field = base + path
+	}
+}
+
+// UseAPIKey sends key with every request and moves the endpoints on the public
+// open-meteo.com hosts to their customer-*.open-meteo.com counterparts, as the
+// commercial plans require. Endpoints on other hosts are left as they are.
+func (c *Client) UseAPIKey(key string) {
+	c.APIKey = key
+	for field := range c.endpoints() {
+		u, err := url.Parse(*field)
+		if err != nil || !strings.HasSuffix(u.Host, ".open-meteo.com") || strings.HasPrefix(u.Host, "customer-") {
+			continue
+		}
+		u.Host = "customer-" + u.Host
+		*field = u.String()
+	}
+}
+
+// requestURL joins endpoint and params, adding the API key when there is one
+func (c *Client) requestURL(endpoint string, params url.Values) string {
+	if c.APIKey != "" {
+		withKey := url.Values{}
+		for name, values := range params {
+			withKey[name] = values
+		}
+		withKey.Set("apikey", c.APIKey)
+		params = withKey
+	}
+	return fmt.Sprintf("%s?%s", endpoint, params.Encode())
+}
+
+var apiKeyPattern = regexp.MustCompile(`(apikey=)[^&\s"]+`)
+
+// MaskAPIKey hides the value of any apikey parameter in s, for URLs and errors that are printed
+func MaskAPIKey(s string) string {
+	return apiKeyPattern.ReplaceAllString(s, "${1}***")
+}
@@ -0,0 +1,159 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// EnsembleModel is the ensemble asked for by Ensemble: DWD ICON with 40 members
+// over Europe and 40 globally, on a coarser grid than the deterministic forecast
+const EnsembleModel = "icon_seamless"
+
+// EnsembleResponse holds the hourly temperature and precipitation of every
+// ensemble member. The API names the series "temperature_2m" for the control
+// run and "temperature_2m_member01" and so on for the members.
+type EnsembleResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+	Time      []string
+	// Members maps a variable to its series, the control run first, then the members in order
+	Members map[string][][]*float64
+}
+
+// UnmarshalJSON groups the member-suffixed hourly series by variable
+func (r *EnsembleResponse) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Latitude  float64                    `json:"latitude"`
+		Longitude float64                    `json:"longitude"`
+		Timezone  string                     `json:"timezone"`
+		Hourly    map[string]json.RawMessage `json:"hourly"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = EnsembleResponse{Latitude: raw.Latitude, Longitude: raw.Longitude, Timezone: raw.Timezone, Members: map[string][][]*float64{}}
+
+	for name, values := range raw.Hourly {
+		if name == "time" {
+			if err := json.Unmarshal(values, &r.Time); err != nil {
+				return err
+			}
+			continue
+		}
+		var series []*float64
+		if err := json.Unmarshal(values, &series); err != nil {
+			return fmt.Errorf("series %s: %w", name, err)
+		}
+
+		variable, member := name, 0
+		if base, suffix, ok := strings.Cut(name, "_member"); ok {
+			n, err := strconv.Atoi(suffix)
+			if err != nil || n < 1 {
+				return fmt.Errorf("series %s: bad member number", name)
+			}
+			variable, member = base, n
+		}
+		for len(r.Members[variable]) <= member {
+			r.Members[variable] = append(r.Members[variable], nil)
+		}
+		r.Members[variable][member] = series
+	}
+	return nil
+}
+
+// Ensemble fetches the hourly temperature and precipitation of every member of
+// EnsembleModel for the place and span in opts. The response is large.
+func (c *Client) Ensemble(ctx context.Context, opts Options) (*EnsembleResponse, error) {
+	if err := ValidateCoordinates(opts.Latitude, opts.Longitude); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(opts.Latitude, 'f', -1, 64))
+	params.Add("longitude", strconv.FormatFloat(opts.Longitude, 'f', -1, 64))
+	params.Add("hourly", "temperature_2m,precipitation")
+	params.Add("models", EnsembleModel)
+	params.Add("timezone", "auto")
+	addSpan(params, opts)
+	if opts.Units == "imperial" {
+		params.Add("temperature_unit", "fahrenheit")
+		params.Add("precipitation_unit", "inch")
+	}
+
+	var ensemble EnsembleResponse
+	_, err := c.fetchCached(ctx, c.EnsembleURL, params, func(body []byte) error {
+		ensemble = EnsembleResponse{}
+		if err := json.Unmarshal(body, &ensemble); err != nil {
+			return &DecodeError{What: "ensemble response", Err: err}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ensemble request failed: %w", err)
+	}
+	return &ensemble, nil
+}
+
+// EnsembleDay summarizes the members for one day
+type EnsembleDay struct {
+	Date string
+	// HighMin and HighMax are the lowest and highest daily maximum temperature of the members
+	HighMin float64
+	HighMax float64
+	// PrecipitationChance is the percentage of members with at least the threshold of precipitation
+	PrecipitationChance float64
+	// Members is how many members had data for the day
+	Members int
+}
+
+// Day aggregates the members over the hours of date (YYYY-MM-DD). Members
+// without any temperature that day are left out; ok is false when none remain.
+func (r *EnsembleResponse) Day(date string, precipitationThreshold float64) (day EnsembleDay, ok bool) {
+	day = EnsembleDay{Date: date, HighMin: math.Inf(1), HighMax: math.Inf(-1)}
+	temperatures, precipitation := r.Members["temperature_2m"], r.Members["precipitation"]
+	wet := 0
+	for m, series := range temperatures {
+		high, found := dayMax(r.Time, series, date)
+		if !found {
+			continue
+		}
+		day.Members++
+		day.HighMin, day.HighMax = math.Min(day.HighMin, high), math.Max(day.HighMax, high)
+		if m < len(precipitation) && daySum(r.Time, precipitation[m], date) >= precipitationThreshold {
+			wet++
+		}
+	}
+	if day.Members == 0 {
+		return EnsembleDay{}, false
+	}
+	day.PrecipitationChance = 100 * float64(wet) / float64(day.Members)
+	return day, true
+}
+
+// dayMax returns the highest non-null value of series during date
+func dayMax(times []string, series []*float64, date string) (float64, bool) {
+	highest, found := math.Inf(-1), false
+	for i, t := range times {
+		if i < len(series) && series[i] != nil && strings.HasPrefix(t, date+"T") {
+			highest, found = math.Max(highest, *series[i]), true
+		}
+	}
+	return highest, found
+}
+
+// daySum adds up the non-null values of series during date
+func daySum(times []string, series []*float64, date string) float64 {
+	sum := 0.0
+	for i, t := range times {
+		if i < len(series) && series[i] != nil && strings.HasPrefix(t, date+"T") {
+			sum += *series[i]
+		}
+	}
+	return sum
+}
@@ -0,0 +1,110 @@
+package weather
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// ensembleJSON has a control run and three members over two days; member03 has
+// no temperatures on the second day, and the members are not in order
+const ensembleJSON = `{
+	"latitude": 52.52,
+	"longitude": 13.41,
+	"timezone": "Europe/Berlin",
+	"hourly": {
+		"time": ["2026-06-01T12:00", "2026-06-01T15:00", "2026-06-02T12:00", "2026-06-02T15:00"],
+		"temperature_2m_member02": [19, 18, 16, 15],
+		"temperature_2m": [20, 22, 18, 19],
+		"temperature_2m_member03": [23, 24, null, null],
+		"temperature_2m_member01": [21, 25, 17, null],
+		"precipitation": [0, 0.5, 2, 1],
+		"precipitation_member01": [1, 1, 0, 0],
+		"precipitation_member02": [0, 0, 0, 0.4],
+		"precipitation_member03": [0.2, 0, 5, 5]
+	}
+}`
+
+func TestEnsembleUnmarshal(t *testing.T) {
+	var r EnsembleResponse
+	if err := json.Unmarshal([]byte(ensembleJSON), &r); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if r.Timezone != "Europe/Berlin" || len(r.Time) != 4 {
+		t.Errorf("got timezone %q and %d times", r.Timezone, len(r.Time))
+	}
+	if len(r.Members) != 2 {
+		t.Errorf("got variables %v, want temperature_2m and precipitation", r.Members)
+	}
+	for variable, members := range r.Members {
+		if len(members) != 4 {
+			t.Errorf("%s has %d series, want the control run and 3 members", variable, len(members))
+		}
+	}
+	// The first hour tells the series apart
+	for m, want := range []float64{20, 21, 19, 23} {
+		if got := r.Members["temperature_2m"][m][0]; got == nil || *got != want {
+			t.Errorf("temperature_2m of member %d starts with %v, want %g", m, got, want)
+		}
+	}
+	if got := r.Members["temperature_2m"][3][2]; got != nil {
+		t.Errorf("a null temperature decoded as %g", *got)
+	}
+}
+
+func TestEnsembleUnmarshalMemberGap(t *testing.T) {
+	var r EnsembleResponse
+	body := `{"hourly": {"time": ["2026-06-01T12:00"], "precipitation": [0], "precipitation_member02": [1]}}`
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	members := r.Members["precipitation"]
+	if len(members) != 3 || members[1] != nil || members[2] == nil {
+		t.Errorf("got %d series for the control run and member02, want member01 left empty", len(members))
+	}
+}
+
+func TestEnsembleUnmarshalBadMember(t *testing.T) {
+	for _, name := range []string{"temperature_2m_member", "temperature_2m_memberx", "temperature_2m_member00", "temperature_2m_member-1"} {
+		var r EnsembleResponse
+		body := `{"hourly": {"time": ["2026-06-01T12:00"], "` + name + `": [20]}}`
+		if err := json.Unmarshal([]byte(body), &r); err == nil {
+			t.Errorf("Unmarshal accepted series %s", name)
+		}
+	}
+}
+
+func TestEnsembleDay(t *testing.T) {
+	var r EnsembleResponse
+	if err := json.Unmarshal([]byte(ensembleJSON), &r); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	tests := []struct {
+		name      string
+		date      string
+		threshold float64
+		want      EnsembleDay
+		ok        bool
+	}{
+		// Highs are 22, 25, 19 and 24; the members' totals 0.5, 2, 0 and 0.2
+		{"first day", "2026-06-01", 0.5, EnsembleDay{Date: "2026-06-01", HighMin: 19, HighMax: 25, PrecipitationChance: 50, Members: 4}, true},
+		{"lower threshold", "2026-06-01", 0.1, EnsembleDay{Date: "2026-06-01", HighMin: 19, HighMax: 25, PrecipitationChance: 75, Members: 4}, true},
+		{"zero threshold", "2026-06-01", 0, EnsembleDay{Date: "2026-06-01", HighMin: 19, HighMax: 25, PrecipitationChance: 100, Members: 4}, true},
+		// member03 has no temperatures, so its 10 mm do not count either
+		{"member without data", "2026-06-02", 0.5, EnsembleDay{Date: "2026-06-02", HighMin: 16, HighMax: 19, PrecipitationChance: 100.0 / 3, Members: 3}, true},
+		{"day outside the forecast", "2026-06-03", 0.5, EnsembleDay{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.Day(tt.date, tt.threshold)
+			if ok != tt.ok {
+				t.Fatalf("Day(%s) ok = %v, want %v", tt.date, ok, tt.ok)
+			}
+			got.PrecipitationChance = math.Round(got.PrecipitationChance*1e9) / 1e9
+			tt.want.PrecipitationChance = math.Round(tt.want.PrecipitationChance*1e9) / 1e9
+			if got != tt.want {
+				t.Errorf("Day(%s, %g) = %+v, want %+v", tt.date, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
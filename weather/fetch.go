@@ -0,0 +1,226 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryBaseDelay is the wait before the first retry; it doubles with every attempt
+const retryBaseDelay = 500 * time.Millisecond
+
+// APIError is returned when the API answers with something other than 200 OK.
+// Reason holds the explanation from the JSON error body, when there was one.
+type APIError struct {
+	StatusCode int
+	Reason     string
+}
+
+func (e *APIError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("API request failed with status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API request failed with status code %d: %s", e.StatusCode, e.Reason)
+}
+
+// DecodeError is returned when a response is not the JSON that was expected
+type DecodeError struct {
+	// What names the response, like "JSON response"
+	What string
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error parsing %s: %v", e.What, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when the API answers 429 Too Many Requests.
+// RetryAfter is the wait the API asked for, or zero when it did not say.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter <= 0 {
+		return "rate limited by the API, try again later"
+	}
+	return fmt.Sprintf("rate limited by the API, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// maxRetryAfter is the longest advised wait that is sat out before retrying;
+// beyond it the error is returned straight away
+const maxRetryAfter = 30 * time.Second
+
+// parseRetryAfter reads a Retry-After header, given either in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return max(time.Until(when), 0)
+	}
+	return 0
+}
+
+// newAPIError decodes an Open-Meteo error body such as
+// {"error":true,"reason":"Latitude must be in range of -90 to 90°."}
+func newAPIError(statusCode int, body []byte) *APIError {
+	var errorBody struct {
+		Reason string `json:"reason"`
+	}
+	// A body that is not JSON leaves Reason empty and the generic message is used
+	json.Unmarshal(body, &errorBody)
+	return &APIError{StatusCode: statusCode, Reason: strings.TrimSpace(errorBody.Reason)}
+}
+
+// isRetryable reports whether a failed request may succeed if sent again.
+// Server errors, rate limits and network problems (including timeouts) are retried,
+// other client errors are not.
+func isRetryable(err error) bool {
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// fetchCached GETs endpoint with params, going through the disk cache when there is one.
+// decode is called on the body, whether cached or fresh, and only bodies it accepts are
+// stored. When the API cannot be reached a stale entry up to MaxStale old stands in;
+// its age is returned, and 0 otherwise.
+func (c *Client) fetchCached(ctx context.Context, endpoint string, params url.Values, decode func([]byte) error) (time.Duration, error) {
+	// Serve from the disk cache when a fresh entry exists
+	key := cacheKey(endpoint, params)
+	if c.Cache != nil {
+		if cached, age, ok := c.Cache.load(key, c.Cache.TTL); ok && decode(cached) == nil {
+			debugf("Using cached response from %s ago", age.Round(time.Second))
+			return 0, nil
+		}
+	}
+
+	body, err := c.fetchBody(ctx, c.requestURL(endpoint, params))
+	if err != nil {
+		// When the API cannot be reached an older answer is better than none
+		if c.Cache != nil && isRetryable(err) {
+			if cached, age, ok := c.Cache.load(key, c.Cache.MaxStale); ok && decode(cached) == nil {
+				logf("Warning: %v; using cached response from %s ago", err, age.Round(time.Second))
+				return age, nil
+			}
+		}
+		return 0, err
+	}
+
+	if err := decode(body); err != nil {
+		return 0, err
+	}
+	if c.Cache != nil {
+		if err := c.Cache.store(key, body); err != nil {
+			logf("Warning: could not cache response: %v", err)
+		}
+	}
+	return 0, nil
+}
+
+// fetchBody GETs fullURL and returns the response body, retrying transient
+// failures with exponential backoff and jitter
+func (c *Client) fetchBody(ctx context.Context, fullURL string) ([]byte, error) {
+	var lastErr error
+	attempts := 0
+	for attempts <= c.Retries {
+		if attempts > 0 {
+			delay := retryBaseDelay << (attempts - 1)
+			delay += time.Duration(rand.Int63n(int64(delay)))
+			// A rate limited request waits as long as the API asked
+			var rateErr *RateLimitError
+			if errors.As(lastErr, &rateErr) && rateErr.RetryAfter > delay {
+				delay = rateErr.RetryAfter
+			}
+			debugf("Request failed (%v), retrying in %s", lastErr, delay.Round(time.Millisecond))
+
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("after %d attempt(s): %w", attempts, lastErr)
+			case <-time.After(delay):
+			}
+		}
+
+		attempts++
+		body, err := c.fetchOnce(ctx, fullURL)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+
+		// Stop early on client errors or when the caller gave up
+		if !isRetryable(err) || ctx.Err() != nil {
+			break
+		}
+		// Do not sit out a long rate limit; the caller can report when to try again
+		var rateErr *RateLimitError
+		if errors.As(err, &rateErr) && rateErr.RetryAfter > maxRetryAfter {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("after %d attempt(s): %w", attempts, lastErr)
+}
+
+// fetchOnce makes a single GET request
+func (c *Client) fetchOnce(ctx context.Context, fullURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		// The error repeats the URL, which must not show the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = MaskAPIKey(urlErr.URL)
+		}
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the response status; bodies are read to the end so the connection can be reused
+	if resp.StatusCode == http.StatusTooManyRequests {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil, &RateLimitError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Error bodies are short; cap the read in case a proxy sends a page of HTML
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, newAPIError(resp.StatusCode, body)
+	}
+
+	// Read the response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	return body, nil
+}
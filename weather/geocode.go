@@ -0,0 +1,90 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GeocodingResponse is the JSON returned by the geocoding search endpoint
+type GeocodingResponse struct {
+	Results []struct {
+		Name       string  `json:"name"`
+		Latitude   float64 `json:"latitude"`
+		Longitude  float64 `json:"longitude"`
+		Country    string  `json:"country"`
+		Admin1     string  `json:"admin1"`
+		Population int     `json:"population"`
+	} `json:"results"`
+}
+
+// GeocodeURL returns the URL GeocodeLocation would request for name, with any API key masked
+func (c *Client) GeocodeURL(name string) string {
+	return MaskAPIKey(c.requestURL(c.GeocodingURL, geocodeParams(name)))
+}
+
+func geocodeParams(name string) url.Values {
+	params := url.Values{}
+	params.Add("name", name)
+	params.Add("count", "10")
+	params.Add("language", "en")
+	params.Add("format", "json")
+	return params
+}
+
+// ErrLocationNotFound is returned when a place name matches nothing
+var ErrLocationNotFound = errors.New("no location found")
+
+// Place is a geocoded location
+type Place struct {
+	// Name is a readable name like "Berlin, Land Berlin, Germany"
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+// GeocodeLocation resolves a place name to coordinates and a readable display name.
+// When several places share the name, the most populous one is returned.
+func (c *Client) GeocodeLocation(ctx context.Context, name string) (Place, error) {
+	body, err := c.fetchBody(ctx, c.requestURL(c.GeocodingURL, geocodeParams(name)))
+	if err != nil {
+		return Place{}, fmt.Errorf("geocoding request failed: %w", err)
+	}
+
+	var geocodingResponse GeocodingResponse
+	if err := json.Unmarshal(body, &geocodingResponse); err != nil {
+		return Place{}, &DecodeError{What: "geocoding response", Err: err}
+	}
+
+	if len(geocodingResponse.Results) == 0 {
+		return Place{}, fmt.Errorf("%w for %q", ErrLocationNotFound, name)
+	}
+
+	// Pick the most populous match, keeping the API's ranking on ties
+	top := geocodingResponse.Results[0]
+	for _, result := range geocodingResponse.Results[1:] {
+		if result.Population > top.Population {
+			top = result
+		}
+	}
+
+	// Build a readable name like "Berlin, Land Berlin, Germany"
+	parts := []string{top.Name}
+	if top.Admin1 != "" && top.Admin1 != top.Name {
+		parts = append(parts, top.Admin1)
+	}
+	if top.Country != "" {
+		parts = append(parts, top.Country)
+	}
+
+	displayName := strings.Join(parts, ", ")
+	if len(geocodingResponse.Results) > 1 {
+		logf("Found %d matches for %q, using %s (population %d)",
+			len(geocodingResponse.Results), name, displayName, top.Population)
+	}
+
+	return Place{Name: displayName, Latitude: top.Latitude, Longitude: top.Longitude}, nil
+}
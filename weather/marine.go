@@ -0,0 +1,95 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// MarineResponse is the daily sea state returned by the marine endpoint.
+// Values are null away from the ocean grid.
+type MarineResponse struct {
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Timezone   string  `json:"timezone"`
+	DailyUnits struct {
+		WaveHeightMax string `json:"wave_height_max"`
+	} `json:"daily_units"`
+	Daily struct {
+		Time []string `json:"time"`
+		// Heights in m or ft, periods in seconds, directions in degrees the waves come from
+		WaveHeightMax         []*float64 `json:"wave_height_max"`
+		WaveDirectionDominant []*float64 `json:"wave_direction_dominant"`
+		WavePeriodMax         []*float64 `json:"wave_period_max"`
+		SwellWaveHeightMax    []*float64 `json:"swell_wave_height_max"`
+	} `json:"daily"`
+}
+
+// HasData reports whether any day has a wave height; inland points only get nulls
+func (r *MarineResponse) HasData() bool {
+	for _, height := range r.Daily.WaveHeightMax {
+		if height != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// MarineSlot is one day of the sea state; values are nil away from the ocean grid
+type MarineSlot struct {
+	// Date is the local date as YYYY-MM-DD
+	Date               string
+	WaveHeightMax      *float64
+	WaveDirection      *float64
+	WavePeriodMax      *float64
+	SwellWaveHeightMax *float64
+}
+
+// DailySlots returns every day of the sea state, lining the daily series up by index
+func (r *MarineResponse) DailySlots() []MarineSlot {
+	d := r.Daily
+	slots := make([]MarineSlot, len(d.Time))
+	for i, date := range d.Time {
+		slots[i] = MarineSlot{
+			Date:               date,
+			WaveHeightMax:      nullableAt(d.WaveHeightMax, i),
+			WaveDirection:      nullableAt(d.WaveDirectionDominant, i),
+			WavePeriodMax:      nullableAt(d.WavePeriodMax, i),
+			SwellWaveHeightMax: nullableAt(d.SwellWaveHeightMax, i),
+		}
+	}
+	return slots
+}
+
+// Marine fetches the daily sea state for the place and span in opts from the
+// Open-Meteo marine API. Imperial units give wave heights in feet.
+func (c *Client) Marine(ctx context.Context, opts Options) (*MarineResponse, error) {
+	if err := ValidateCoordinates(opts.Latitude, opts.Longitude); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(opts.Latitude, 'f', -1, 64))
+	params.Add("longitude", strconv.FormatFloat(opts.Longitude, 'f', -1, 64))
+	params.Add("daily", "wave_height_max,wave_direction_dominant,wave_period_max,swell_wave_height_max")
+	params.Add("timezone", "auto")
+	addSpan(params, opts)
+	if opts.Units == "imperial" {
+		params.Add("length_unit", "imperial")
+	}
+
+	var marine MarineResponse
+	_, err := c.fetchCached(ctx, c.MarineURL, params, func(body []byte) error {
+		marine = MarineResponse{}
+		if err := json.Unmarshal(body, &marine); err != nil {
+			return &DecodeError{What: "marine response", Err: err}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marine request failed: %w", err)
+	}
+	return &marine, nil
+}
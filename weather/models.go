@@ -0,0 +1,91 @@
+package weather
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Model is a weather model that can be picked with Options.Model
+type Model struct {
+	Name        string
+	Description string
+}
+
+// Models lists the forecast models Open-Meteo serves. Regional models only
+// cover part of the globe; elsewhere their series are null.
+var Models = []Model{
+	{"best_match", "the best models for the location, combined (default)"},
+	{"ecmwf_ifs04", "ECMWF IFS 0.4°, global"},
+	{"ecmwf_ifs025", "ECMWF IFS 0.25°, global"},
+	{"ecmwf_aifs025", "ECMWF AIFS 0.25°, global"},
+	{"gfs_seamless", "NOAA GFS, global, combined with HRRR over the US"},
+	{"gfs_global", "NOAA GFS 0.25°, global"},
+	{"icon_seamless", "DWD ICON, global, combined with ICON-EU and ICON-D2"},
+	{"icon_global", "DWD ICON 11 km, global"},
+	{"icon_eu", "DWD ICON-EU 7 km, Europe"},
+	{"icon_d2", "DWD ICON-D2 2 km, Central Europe"},
+	{"gem_seamless", "Environment Canada GEM, global and regional"},
+	{"meteofrance_seamless", "Météo-France ARPEGE and AROME"},
+	{"jma_seamless", "JMA GSM and MSM, Japan"},
+	{"ukmo_seamless", "UK Met Office, global and UK"},
+	{"metno_nordic", "MET Norway Nordic 1 km, Scandinavia"},
+	{"knmi_seamless", "KNMI HARMONIE, Netherlands and Europe"},
+	{"dmi_seamless", "DMI HARMONIE, Northern Europe"},
+	{"cma_grapes_global", "CMA GRAPES, global"},
+	{"bom_access_global", "BOM ACCESS-G, global"},
+}
+
+// IsModel reports whether name is one of Models
+func IsModel(name string) bool {
+	for _, model := range Models {
+		if model.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// dropNullSeries clears the hourly and daily series of r that are null
+// throughout body, so they read as missing instead of as zeros, and returns
+// their API names. A model that does not cover a place or a variable sends such series.
+func dropNullSeries(body []byte, r *WeatherResponse) []string {
+	var raw struct {
+		Hourly map[string][]json.RawMessage `json:"hourly"`
+		Daily  map[string][]json.RawMessage `json:"daily"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil
+	}
+
+	var dropped []string
+	for _, section := range []struct {
+		series map[string][]json.RawMessage
+		target reflect.Value
+	}{
+		{raw.Hourly, reflect.ValueOf(&r.Hourly).Elem()},
+		{raw.Daily, reflect.ValueOf(&r.Daily).Elem()},
+	} {
+		for i := 0; i < section.target.NumField(); i++ {
+			name := strings.Split(section.target.Type().Field(i).Tag.Get("json"), ",")[0]
+			values, ok := section.series[name]
+			if name == "time" || !ok || len(values) == 0 || !allNull(values) {
+				continue
+			}
+			section.target.Field(i).Set(reflect.Zero(section.target.Field(i).Type()))
+			dropped = append(dropped, name)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+func allNull(values []json.RawMessage) bool {
+	for _, v := range values {
+		if string(v) != "null" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package weather
+
+import "math"
+
+// Weights of OutdoorScore: points per percent of precipitation probability,
+// per km/h of wind above outdoorCalmWind and per °C outside the comfort band
+const (
+	outdoorRainWeight        = 1
+	outdoorWindWeight        = 2
+	outdoorTemperatureWeight = 5
+	outdoorCalmWind          = 15
+)
+
+// ComfortBand is the range of temperatures that counts as comfortable for
+// being outdoors, in °C or in °F for imperial units
+type ComfortBand struct {
+	Min float64
+	Max float64
+}
+
+// DefaultComfortBand is 18–24°C, or 64–75°F when units is "imperial"
+func DefaultComfortBand(units string) ComfortBand {
+	if units == "imperial" {
+		return ComfortBand{Min: 64, Max: 75}
+	}
+	return ComfortBand{Min: 18, Max: 24}
+}
+
+// OutdoorScore rates an hour for outdoor plans, lower being better: 0 is a dry,
+// calm hour within band. It weighs the precipitation probability, wind above
+// 15 km/h and the distance of the temperature from band. units is "metric" or
+// "imperial" as in Options; the wind and temperature are weighed in km/h and °C
+// either way, so the score does not depend on the unit system. Missing values
+// count as fine, except that precipitation without a probability counts as certain.
+func OutdoorScore(hour HourlySlot, band ComfortBand, units string) float64 {
+	score := 0.0
+	switch {
+	case hour.PrecipitationProbability != nil:
+		score += outdoorRainWeight * *hour.PrecipitationProbability
+	case hour.Precipitation != nil && *hour.Precipitation > 0:
+		score += outdoorRainWeight * 100
+	}
+
+	if hour.WindSpeed != nil {
+		wind := *hour.WindSpeed
+		if units == "imperial" {
+			wind *= 1.609344
+		}
+		score += outdoorWindWeight * math.Max(0, wind-outdoorCalmWind)
+	}
+
+	if hour.Temperature != nil {
+		deviation := math.Max(band.Min-*hour.Temperature, *hour.Temperature-band.Max)
+		if units == "imperial" {
+			deviation /= 1.8
+		}
+		score += outdoorTemperatureWeight * math.Max(0, deviation)
+	}
+	return score
+}
@@ -0,0 +1,39 @@
+package weather
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOutdoorScore(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name  string
+		hour  HourlySlot
+		units string
+		want  float64
+	}{
+		{"ideal", HourlySlot{PrecipitationProbability: value(0), WindSpeed: value(10), Temperature: value(20)}, "metric", 0},
+		{"nothing known", HourlySlot{}, "metric", 0},
+		{"chance of rain", HourlySlot{PrecipitationProbability: value(40), Precipitation: value(3)}, "metric", 40},
+		{"rain without a probability", HourlySlot{Precipitation: value(1.2)}, "metric", 100},
+		{"dry without a probability", HourlySlot{Precipitation: value(0)}, "metric", 0},
+		{"calm wind limit", HourlySlot{WindSpeed: value(15)}, "metric", 0},
+		{"windy", HourlySlot{WindSpeed: value(25)}, "metric", 20},
+		{"band edges", HourlySlot{Temperature: value(18)}, "metric", 0},
+		{"too cold", HourlySlot{Temperature: value(14)}, "metric", 20},
+		{"too warm", HourlySlot{Temperature: value(26)}, "metric", 10},
+		{"everything", HourlySlot{PrecipitationProbability: value(10), WindSpeed: value(20), Temperature: value(28)}, "metric", 40},
+		// 20 mph is 32.19 km/h and 84°F is 5°C above the 64–75°F band
+		{"imperial wind", HourlySlot{WindSpeed: value(20)}, "imperial", 2 * (20*1.609344 - 15)},
+		{"imperial temperature", HourlySlot{Temperature: value(84)}, "imperial", 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			band := DefaultComfortBand(tt.units)
+			if got := OutdoorScore(tt.hour, band, tt.units); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("OutdoorScore = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
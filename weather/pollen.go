@@ -0,0 +1,96 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// PollenSpecies are the pollen the air quality API forecasts, in Europe only
+var PollenSpecies = []string{"alder", "birch", "grass", "mugwort", "olive", "ragweed"}
+
+// PollenResponse is the current pollen count per species from the air quality endpoint.
+// Outside the covered area every count is null.
+type PollenResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+	Current   struct {
+		Time string `json:"time"`
+		// Grains per m³
+		Alder   *float64 `json:"alder_pollen"`
+		Birch   *float64 `json:"birch_pollen"`
+		Grass   *float64 `json:"grass_pollen"`
+		Mugwort *float64 `json:"mugwort_pollen"`
+		Olive   *float64 `json:"olive_pollen"`
+		Ragweed *float64 `json:"ragweed_pollen"`
+	} `json:"current"`
+}
+
+// Counts returns the current count of every species in PollenSpecies, nil where there is none
+func (r *PollenResponse) Counts() map[string]*float64 {
+	return map[string]*float64{
+		"alder":   r.Current.Alder,
+		"birch":   r.Current.Birch,
+		"grass":   r.Current.Grass,
+		"mugwort": r.Current.Mugwort,
+		"olive":   r.Current.Olive,
+		"ragweed": r.Current.Ragweed,
+	}
+}
+
+// Pollen fetches the current pollen counts for the coordinates from the Open-Meteo
+// air quality API, through the same retries and cache as Forecast
+func (c *Client) Pollen(ctx context.Context, latitude, longitude float64) (*PollenResponse, error) {
+	if err := ValidateCoordinates(latitude, longitude); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(latitude, 'f', -1, 64))
+	params.Add("longitude", strconv.FormatFloat(longitude, 'f', -1, 64))
+	params.Add("current", "alder_pollen,birch_pollen,grass_pollen,mugwort_pollen,olive_pollen,ragweed_pollen")
+	params.Add("timezone", "auto")
+
+	var pollen PollenResponse
+	_, err := c.fetchCached(ctx, c.AirQualityURL, params, func(body []byte) error {
+		pollen = PollenResponse{}
+		if err := json.Unmarshal(body, &pollen); err != nil {
+			return &DecodeError{What: "pollen response", Err: err}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pollen request failed: %w", err)
+	}
+	return &pollen, nil
+}
+
+// pollenBands are the counts in grains/m³ from which a species is Medium and High.
+// Grass and ragweed pollen cause symptoms at lower counts than tree pollen.
+var pollenBands = map[string][2]float64{
+	"alder":   {15, 90},
+	"birch":   {15, 90},
+	"olive":   {15, 90},
+	"grass":   {5, 20},
+	"mugwort": {10, 50},
+	"ragweed": {5, 20},
+}
+
+// PollenLevel maps a pollen count in grains/m³ to "Low", "Medium" or "High"
+func PollenLevel(species string, grains float64) string {
+	bands, ok := pollenBands[species]
+	if !ok {
+		bands = pollenBands["alder"]
+	}
+	switch {
+	case grains >= bands[1]:
+		return "High"
+	case grains >= bands[0]:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
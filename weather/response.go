@@ -0,0 +1,216 @@
+package weather
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxForecastDays is the longest forecast Open-Meteo will return
+const MaxForecastDays = 16
+
+// ArchiveStart is the first day of the historical archive
+const ArchiveStart = "1940-01-01"
+
+// WeatherResponse is the forecast JSON returned by the API
+type WeatherResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+	// Offset from UTC at the time of the request, used when tzdata is unavailable
+	UTCOffsetSeconds int `json:"utc_offset_seconds"`
+	// CacheAge is set when a stale cached response stood in for a failed request
+	CacheAge time.Duration `json:"-"`
+	// Unavailable names the series that were null throughout and are left empty
+	Unavailable []string `json:"-"`
+	// Incomplete names the requested series that were absent or shorter than their times
+	Incomplete []string `json:"-"`
+	// HourlyTimes are Hourly.Time parsed in the forecast's timezone; see ParseTimes
+	HourlyTimes []time.Time `json:"-"`
+	// location caches TimeLocation for the zone named zone
+	location *time.Location
+	zone     string
+	// Units the values were fetched with, as reported by the API
+	HourlyUnits struct {
+		Temperature2m string `json:"temperature_2m"`
+		Precipitation string `json:"precipitation"`
+		WindSpeed10m  string `json:"wind_speed_10m"`
+		Snowfall      string `json:"snowfall"`
+		SnowDepth     string `json:"snow_depth"`
+		// SurfacePressure is hPa in either unit system
+		SurfacePressure string `json:"surface_pressure"`
+	} `json:"hourly_units"`
+	DailyUnits struct {
+		Temperature2mMax string `json:"temperature_2m_max"`
+		PrecipitationSum string `json:"precipitation_sum"`
+		RainSum          string `json:"rain_sum"`
+		WindSpeed10mMax  string `json:"wind_speed_10m_max"`
+	} `json:"daily_units"`
+	Current struct {
+		Time                string      `json:"time"`
+		Temperature2m       float64     `json:"temperature_2m"`
+		ApparentTemperature float64     `json:"apparent_temperature"`
+		WeatherCode         WeatherCode `json:"weather_code"`
+		WindSpeed10m        float64     `json:"wind_speed_10m"`
+		WindDirection10m    float64     `json:"wind_direction_10m"`
+		RelativeHumidity2m  float64     `json:"relative_humidity_2m"`
+		IsDay               int         `json:"is_day"`
+		// Air pressure at sea level in hPa
+		PressureMSL float64 `json:"pressure_msl"`
+	} `json:"current"`
+	Hourly struct {
+		Time                []string  `json:"time"`
+		Temperature2m       []float64 `json:"temperature_2m"`
+		ApparentTemperature []float64 `json:"apparent_temperature"`
+		// Probabilities are null beyond the models' probability horizon
+		PrecipitationProbability []*float64    `json:"precipitation_probability"`
+		Precipitation            []float64     `json:"precipitation"`
+		WeatherCode              []WeatherCode `json:"weather_code"`
+		RelativeHumidity2m       []float64     `json:"relative_humidity_2m"`
+		DewPoint2m               []float64     `json:"dew_point_2m"`
+		WindSpeed10m             []float64     `json:"wind_speed_10m"`
+		WindDirection10m         []float64     `json:"wind_direction_10m"`
+		WindGusts10m             []float64     `json:"wind_gusts_10m"`
+		UVIndex                  []float64     `json:"uv_index"`
+		IsDay                    []int         `json:"is_day"`
+		// Fresh snow in cm or inches, and the snow on the ground in m or ft
+		Snowfall  []float64 `json:"snowfall"`
+		SnowDepth []float64 `json:"snow_depth"`
+		// Percent of the sky covered, in total and by low, mid and high clouds
+		CloudCover     []float64 `json:"cloud_cover"`
+		CloudCoverLow  []float64 `json:"cloud_cover_low"`
+		CloudCoverMid  []float64 `json:"cloud_cover_mid"`
+		CloudCoverHigh []float64 `json:"cloud_cover_high"`
+		// Pressure reduced to sea level, and at the ground where it depends on the elevation
+		PressureMSL     []float64 `json:"pressure_msl"`
+		SurfacePressure []float64 `json:"surface_pressure"`
+	} `json:"hourly"`
+	Daily struct {
+		Time                         []string      `json:"time"`
+		Temperature2mMax             []float64     `json:"temperature_2m_max"`
+		Temperature2mMin             []float64     `json:"temperature_2m_min"`
+		ApparentTemperatureMax       []float64     `json:"apparent_temperature_max"`
+		ApparentTemperatureMin       []float64     `json:"apparent_temperature_min"`
+		PrecipitationSum             []float64     `json:"precipitation_sum"`
+		RainSum                      []float64     `json:"rain_sum"`
+		PrecipitationHours           []float64     `json:"precipitation_hours"`
+		SnowfallSum                  []float64     `json:"snowfall_sum"`
+		PrecipitationProbabilityMax  []*float64    `json:"precipitation_probability_max"`
+		PrecipitationProbabilityMean []*float64    `json:"precipitation_probability_mean"`
+		WindSpeed10mMax              []float64     `json:"wind_speed_10m_max"`
+		WindGusts10mMax              []float64     `json:"wind_gusts_10m_max"`
+		WindDirection10mDominant     []float64     `json:"wind_direction_10m_dominant"`
+		Sunrise                      []string      `json:"sunrise"`
+		Sunset                       []string      `json:"sunset"`
+		DaylightDuration             []float64     `json:"daylight_duration"`
+		UVIndexMax                   []float64     `json:"uv_index_max"`
+		WeatherCode                  []WeatherCode `json:"weather_code"`
+	} `json:"daily"`
+}
+
+// TimeLocation returns the forecast's timezone, in which its local times are given.
+// It falls back to the fixed offset the API reported when the system has no
+// zoneinfo database. The zone is loaded once per Timezone, so the fallback is
+// only warned about once; Forecast resolves it while decoding.
+func (r *WeatherResponse) TimeLocation() *time.Location {
+	if r.location != nil && r.zone == r.Timezone {
+		return r.location
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		logf("Could not load timezone %s (%v), using UTC offset %ds", r.Timezone, err, r.UTCOffsetSeconds)
+		loc = time.FixedZone(r.Timezone, r.UTCOffsetSeconds)
+	}
+	r.location, r.zone = loc, r.Timezone
+	return loc
+}
+
+// CurrentHourIndex returns the index of the hourly slot containing the current time
+// in the forecast's timezone, or of the first slot after it when fromNextHour is set.
+// It falls back to 0 when no slot matches.
+func (r *WeatherResponse) CurrentHourIndex(fromNextHour bool) (int, error) {
+	return r.HourIndexAt(time.Now(), fromNextHour)
+}
+
+// ParseTimes parses the hourly times in the forecast's timezone into HourlyTimes.
+// Forecast calls it while decoding, so a malformed time fails the response once
+// instead of every consumer skipping it.
+func (r *WeatherResponse) ParseTimes() error {
+	loc := r.TimeLocation()
+	times := make([]time.Time, len(r.Hourly.Time))
+	for i, timeStr := range r.Hourly.Time {
+		t, err := time.ParseInLocation("2006-01-02T15:04", timeStr, loc)
+		if err != nil {
+			return fmt.Errorf("malformed hourly time %q: %w", timeStr, err)
+		}
+		times[i] = t
+	}
+	r.HourlyTimes = times
+	return nil
+}
+
+// MatchLengths lines the hourly and daily series up with their times and
+// returns the API names of the requested series that do not cover them: absent
+// ones and ones with fewer values than times, whose missing values read as n/a.
+// Longer series are cut to the times. Series in Unavailable are not repeated.
+func (r *WeatherResponse) MatchLengths(requested url.Values) []string {
+	var incomplete []string
+	for _, section := range []struct {
+		name   string
+		series reflect.Value
+	}{
+		{"hourly", reflect.ValueOf(&r.Hourly).Elem()},
+		{"daily", reflect.ValueOf(&r.Daily).Elem()},
+	} {
+		names := strings.Split(requested.Get(section.name), ",")
+		want := section.series.FieldByName("Time").Len()
+		for i := 0; i < section.series.NumField(); i++ {
+			field := section.series.Field(i)
+			name := strings.Split(section.series.Type().Field(i).Tag.Get("json"), ",")[0]
+			switch {
+			case field.Kind() != reflect.Slice || name == "time":
+			case field.Len() > want:
+				field.Set(field.Slice(0, want))
+			case field.Len() < want && slices.Contains(names, name) && !slices.Contains(r.Unavailable, name):
+				incomplete = append(incomplete, name)
+			}
+		}
+	}
+	sort.Strings(incomplete)
+	return incomplete
+}
+
+// HourIndexAt is CurrentHourIndex for the given moment instead of the current time,
+// which makes the slot selection reproducible for fixed time series
+func (r *WeatherResponse) HourIndexAt(now time.Time, fromNextHour bool) (int, error) {
+	if len(r.HourlyTimes) != len(r.Hourly.Time) {
+		if err := r.ParseTimes(); err != nil {
+			return 0, err
+		}
+	}
+	times := r.HourlyTimes
+
+	// Get current time in the weather location's timezone
+	currentTime := now.In(r.TimeLocation())
+	debugf("Current time in %s: %s", r.Timezone, currentTime.Format("2006-01-02 15:04:05"))
+
+	// next is the first slot after now; the slot before it contains now if it started less than an hour ago.
+	// Otherwise now falls before the series or in a gap, and the next slot is the closest one ahead.
+	next := sort.Search(len(times), func(i int) bool { return times[i].After(currentTime) })
+	switch {
+	case !fromNextHour && next > 0 && currentTime.Sub(times[next-1]) < time.Hour:
+		debugf("Found current forecast time: %s (index %d)", times[next-1].Format("2006-01-02 15:04"), next-1)
+		return next - 1, nil
+	case next < len(times):
+		debugf("Found next forecast time: %s (index %d)", times[next].Format("2006-01-02 15:04"), next)
+		return next, nil
+	}
+
+	// If we can't find a future hour, start from the beginning
+	debugf("No future forecast times found, starting from beginning")
+	return 0, nil
+}
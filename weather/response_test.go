@@ -0,0 +1,200 @@
+package weather
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hourlyResponse returns a response with the given local hourly times in zone
+func hourlyResponse(zone string, times ...string) *WeatherResponse {
+	r := &WeatherResponse{Timezone: zone}
+	r.Hourly.Time = times
+	return r
+}
+
+func TestHourIndexAtHourBoundaries(t *testing.T) {
+	r := hourlyResponse("UTC", "2026-06-01T10:00", "2026-06-01T11:00", "2026-06-01T12:00")
+	tests := []struct {
+		name         string
+		now          string
+		fromNextHour bool
+		want         int
+	}{
+		{"start of the first hour", "2026-06-01T10:00:00Z", false, 0},
+		{"end of the first hour", "2026-06-01T10:59:59Z", false, 0},
+		{"start of the second hour", "2026-06-01T11:00:00Z", false, 1},
+		{"inside the last hour", "2026-06-01T12:30:00Z", false, 2},
+		{"next hour at the start of an hour", "2026-06-01T10:00:00Z", true, 1},
+		{"next hour inside an hour", "2026-06-01T10:30:00Z", true, 1},
+		{"next hour just before a boundary", "2026-06-01T11:59:59Z", true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.HourIndexAt(now, tt.fromNextHour)
+			if err != nil {
+				t.Fatalf("HourIndexAt: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HourIndexAt(%s, %v) = %d, want %d", tt.now, tt.fromNextHour, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHourIndexAtDST(t *testing.T) {
+	tests := []struct {
+		name  string
+		times []string
+		now   string
+		want  int
+	}{
+		// Clocks in Berlin go from 02:00 CET to 03:00 CEST, so 02:00 is skipped
+		{"before spring forward", []string{"2026-03-29T01:00", "2026-03-29T03:00", "2026-03-29T04:00"}, "2026-03-29T00:30:00Z", 0},
+		{"after spring forward", []string{"2026-03-29T01:00", "2026-03-29T03:00", "2026-03-29T04:00"}, "2026-03-29T01:10:00Z", 1},
+		{"an hour after spring forward", []string{"2026-03-29T01:00", "2026-03-29T03:00", "2026-03-29T04:00"}, "2026-03-29T02:00:00Z", 2},
+		// Clocks go back from 03:00 CEST to 02:00 CET; the hours either side are unambiguous
+		{"before fall back", []string{"2026-10-25T01:00", "2026-10-25T04:00", "2026-10-25T05:00"}, "2026-10-24T23:30:00Z", 0},
+		{"after fall back", []string{"2026-10-25T01:00", "2026-10-25T04:00", "2026-10-25T05:00"}, "2026-10-25T03:15:00Z", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := hourlyResponse("Europe/Berlin", tt.times...)
+			now, err := time.Parse(time.RFC3339, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.HourIndexAt(now, false)
+			if err != nil {
+				t.Fatalf("HourIndexAt: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HourIndexAt(%s) = %d (%s), want %d (%s)", tt.now, got, tt.times[got], tt.want, tt.times[tt.want])
+			}
+		})
+	}
+}
+
+func TestTimeLocationFallback(t *testing.T) {
+	var diagnostics strings.Builder
+	Diagnostics = &diagnostics
+	t.Cleanup(func() { Diagnostics = io.Discard })
+
+	times := []string{"2026-06-01T10:00", "2026-06-01T11:00", "2026-06-01T12:00"}
+	known := hourlyResponse("Europe/Berlin", times...)
+	bogus := hourlyResponse("Europe/Atlantis", times...)
+	bogus.UTCOffsetSeconds = 2 * 60 * 60
+
+	loc := bogus.TimeLocation()
+	if name, offset := time.Date(2026, 6, 1, 10, 0, 0, 0, loc).Zone(); name != "Europe/Atlantis" || offset != 7200 {
+		t.Errorf("fallback zone is %s%+d, want Europe/Atlantis+7200", name, offset)
+	}
+
+	for _, now := range []string{"2026-06-01T08:30:00Z", "2026-06-01T09:00:00Z", "2026-06-01T10:59:00Z"} {
+		at, err := time.Parse(time.RFC3339, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := known.HourIndexAt(at, false)
+		if err != nil {
+			t.Fatalf("HourIndexAt in Europe/Berlin: %v", err)
+		}
+		got, err := bogus.HourIndexAt(at, false)
+		if err != nil {
+			t.Fatalf("HourIndexAt with the offset fallback: %v", err)
+		}
+		if got != want {
+			t.Errorf("HourIndexAt(%s) with the offset fallback = %d, want %d as in Europe/Berlin", now, got, want)
+		}
+	}
+
+	if n := strings.Count(diagnostics.String(), "Could not load timezone"); n != 1 {
+		t.Errorf("warned %d times about the timezone, want once:\n%s", n, diagnostics.String())
+	}
+}
+
+func TestHourIndexAt(t *testing.T) {
+	times := []string{"2026-07-14T06:00", "2026-07-14T07:00", "2026-07-14T08:00", "2026-07-14T09:00"}
+	tests := []struct {
+		name         string
+		zone         string
+		now          string
+		fromNextHour bool
+		want         int
+		times        []string
+	}{
+		{"before all hours", "America/New_York", "2026-07-14T08:00:00Z", false, 0, nil},
+		{"next hour before all hours", "America/New_York", "2026-07-14T08:00:00Z", true, 0, nil},
+		{"in the middle", "America/New_York", "2026-07-14T11:45:00Z", false, 1, nil},
+		{"next hour in the middle", "America/New_York", "2026-07-14T11:45:00Z", true, 2, nil},
+		{"in the last hour", "America/New_York", "2026-07-14T13:30:00Z", false, 3, nil},
+		{"after all hours", "America/New_York", "2026-07-14T14:00:00Z", false, 0, nil},
+		{"next hour in the last hour", "America/New_York", "2026-07-14T13:30:00Z", true, 0, nil},
+		{"a day later", "America/New_York", "2026-07-15T11:45:00Z", false, 0, nil},
+		// An unknown zone falls back to the offset of -4h, which is New York's in July
+		{"invalid timezone", "America/Nowhere", "2026-07-14T11:45:00Z", false, 1, nil},
+		{"next hour with an invalid timezone", "America/Nowhere", "2026-07-14T11:45:00Z", true, 2, nil},
+		// 12:00 falls in the gap between 10:00 and 14:00, so the next slot is the closest one
+		{"in a gap", "America/New_York", "2026-07-14T16:00:00Z", false, 1, []string{"2026-07-14T10:00", "2026-07-14T14:00"}},
+		{"next hour in a gap", "America/New_York", "2026-07-14T16:00:00Z", true, 1, []string{"2026-07-14T10:00", "2026-07-14T14:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hours := times
+			if tt.times != nil {
+				hours = tt.times
+			}
+			r := hourlyResponse(tt.zone, hours...)
+			r.UTCOffsetSeconds = -4 * 60 * 60
+			now, err := time.Parse(time.RFC3339, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.HourIndexAt(now, tt.fromNextHour)
+			if err != nil {
+				t.Fatalf("HourIndexAt: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HourIndexAt(%s, %v) = %d, want %d", tt.now, tt.fromNextHour, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHourIndexAtPastDays(t *testing.T) {
+	// With past_days=2 the hourly series starts two days before today
+	start := time.Date(2026, 7, 12, 0, 0, 0, 0, time.UTC)
+	times := make([]string, 4*24)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Hour).Format("2006-01-02T15:04")
+	}
+	r := hourlyResponse("Europe/Berlin", times...)
+	tests := []struct {
+		now          string
+		fromNextHour bool
+		want         int
+	}{
+		// 16:30 in Berlin on the third day is hour 2*24+16
+		{"2026-07-14T14:30:00Z", false, 64},
+		{"2026-07-14T14:30:00Z", true, 65},
+		{"2026-07-14T22:00:00Z", false, 72},
+	}
+	for _, tt := range tests {
+		now, err := time.Parse(time.RFC3339, tt.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := r.HourIndexAt(now, tt.fromNextHour)
+		if err != nil {
+			t.Fatalf("HourIndexAt: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("HourIndexAt(%s, %v) = %d (%s), want %d (%s)", tt.now, tt.fromNextHour, got, times[got], tt.want, times[tt.want])
+		}
+	}
+}
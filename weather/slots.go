@@ -0,0 +1,162 @@
+package weather
+
+import "time"
+
+// HourlySlot is one hour of the forecast with the values of every hourly series.
+// Values are nil where a series is absent or too short; see Incomplete.
+type HourlySlot struct {
+	// Time is the local ISO time as the API gives it, At the same parsed in the forecast's timezone
+	Time                     string
+	At                       time.Time
+	Temperature              *float64
+	ApparentTemperature      *float64
+	PrecipitationProbability *float64
+	Precipitation            *float64
+	WeatherCode              *WeatherCode
+	RelativeHumidity         *float64
+	DewPoint                 *float64
+	WindSpeed                *float64
+	WindDirection            *float64
+	WindGusts                *float64
+	UVIndex                  *float64
+	IsDay                    bool
+	Snowfall                 *float64
+	SnowDepth                *float64
+	CloudCover               *float64
+	CloudCoverLow            *float64
+	CloudCoverMid            *float64
+	CloudCoverHigh           *float64
+	PressureMSL              *float64
+	SurfacePressure          *float64
+}
+
+// DailySlot is one day of the forecast with the values of every daily series
+type DailySlot struct {
+	// Date is the local date as YYYY-MM-DD
+	Date                         string
+	TemperatureMax               *float64
+	TemperatureMin               *float64
+	ApparentTemperatureMax       *float64
+	ApparentTemperatureMin       *float64
+	PrecipitationSum             *float64
+	RainSum                      *float64
+	PrecipitationHours           *float64
+	SnowfallSum                  *float64
+	PrecipitationProbabilityMax  *float64
+	PrecipitationProbabilityMean *float64
+	WindSpeedMax                 *float64
+	WindGustsMax                 *float64
+	WindDirection                *float64
+	// Local ISO times; empty when the sun does not rise or set that day
+	Sunrise          string
+	Sunset           string
+	DaylightDuration *float64
+	UVIndexMax       *float64
+	WeatherCode      *WeatherCode
+}
+
+// valueAt returns the value at index i, or nil when the series is too short
+func valueAt[T any](values []T, i int) *T {
+	if i < 0 || i >= len(values) {
+		return nil
+	}
+	v := values[i]
+	return &v
+}
+
+// nullableAt is valueAt for series that may contain nulls, which are nil as well
+func nullableAt(values []*float64, i int) *float64 {
+	if i < 0 || i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
+
+// stringAt returns the value at index i, or "" when the series is too short
+func stringAt(values []string, i int) string {
+	if i < 0 || i >= len(values) {
+		return ""
+	}
+	return values[i]
+}
+
+// HourlySlots returns every hour of the forecast, lining the hourly series up by index
+func (r *WeatherResponse) HourlySlots() []HourlySlot {
+	if len(r.HourlyTimes) != len(r.Hourly.Time) {
+		if err := r.ParseTimes(); err != nil {
+			logf("Warning: %v", err)
+		}
+	}
+	h := r.Hourly
+	slots := make([]HourlySlot, len(h.Time))
+	for i, timeStr := range h.Time {
+		slots[i] = HourlySlot{
+			Time:                     timeStr,
+			Temperature:              valueAt(h.Temperature2m, i),
+			ApparentTemperature:      valueAt(h.ApparentTemperature, i),
+			PrecipitationProbability: nullableAt(h.PrecipitationProbability, i),
+			Precipitation:            valueAt(h.Precipitation, i),
+			WeatherCode:              valueAt(h.WeatherCode, i),
+			RelativeHumidity:         valueAt(h.RelativeHumidity2m, i),
+			DewPoint:                 valueAt(h.DewPoint2m, i),
+			WindSpeed:                valueAt(h.WindSpeed10m, i),
+			WindDirection:            valueAt(h.WindDirection10m, i),
+			WindGusts:                valueAt(h.WindGusts10m, i),
+			UVIndex:                  valueAt(h.UVIndex, i),
+			IsDay:                    i < len(h.IsDay) && h.IsDay[i] == 1,
+			Snowfall:                 valueAt(h.Snowfall, i),
+			SnowDepth:                valueAt(h.SnowDepth, i),
+			CloudCover:               valueAt(h.CloudCover, i),
+			CloudCoverLow:            valueAt(h.CloudCoverLow, i),
+			CloudCoverMid:            valueAt(h.CloudCoverMid, i),
+			CloudCoverHigh:           valueAt(h.CloudCoverHigh, i),
+			PressureMSL:              valueAt(h.PressureMSL, i),
+			SurfacePressure:          valueAt(h.SurfacePressure, i),
+		}
+		if i < len(r.HourlyTimes) {
+			slots[i].At = r.HourlyTimes[i]
+		}
+	}
+	return slots
+}
+
+// HourlyFrom returns up to n hours starting with the one that contains t, or
+// with the first hour when t is outside the forecast as HourIndexAt does
+func (r *WeatherResponse) HourlyFrom(t time.Time, n int) []HourlySlot {
+	idx, err := r.HourIndexAt(t, false)
+	if err != nil {
+		return nil
+	}
+	slots := r.HourlySlots()
+	return slots[idx:min(idx+max(n, 0), len(slots))]
+}
+
+// DailySlots returns every day of the forecast, lining the daily series up by index
+func (r *WeatherResponse) DailySlots() []DailySlot {
+	d := r.Daily
+	slots := make([]DailySlot, len(d.Time))
+	for i, date := range d.Time {
+		slots[i] = DailySlot{
+			Date:                         date,
+			TemperatureMax:               valueAt(d.Temperature2mMax, i),
+			TemperatureMin:               valueAt(d.Temperature2mMin, i),
+			ApparentTemperatureMax:       valueAt(d.ApparentTemperatureMax, i),
+			ApparentTemperatureMin:       valueAt(d.ApparentTemperatureMin, i),
+			PrecipitationSum:             valueAt(d.PrecipitationSum, i),
+			RainSum:                      valueAt(d.RainSum, i),
+			PrecipitationHours:           valueAt(d.PrecipitationHours, i),
+			SnowfallSum:                  valueAt(d.SnowfallSum, i),
+			PrecipitationProbabilityMax:  nullableAt(d.PrecipitationProbabilityMax, i),
+			PrecipitationProbabilityMean: nullableAt(d.PrecipitationProbabilityMean, i),
+			WindSpeedMax:                 valueAt(d.WindSpeed10mMax, i),
+			WindGustsMax:                 valueAt(d.WindGusts10mMax, i),
+			WindDirection:                valueAt(d.WindDirection10mDominant, i),
+			Sunrise:                      stringAt(d.Sunrise, i),
+			Sunset:                       stringAt(d.Sunset, i),
+			DaylightDuration:             valueAt(d.DaylightDuration, i),
+			UVIndexMax:                   valueAt(d.UVIndexMax, i),
+			WeatherCode:                  valueAt(d.WeatherCode, i),
+		}
+	}
+	return slots
+}
@@ -0,0 +1,18 @@
+package weather
+
+// UVRisk maps a UV index to its WHO exposure category and the protection
+// recommended for it
+func UVRisk(index float64) (category, protection string) {
+	switch {
+	case index < 3:
+		return "Low", "no protection needed"
+	case index < 6:
+		return "Moderate", "seek shade at midday, wear a hat and sunscreen"
+	case index < 8:
+		return "High", "seek shade at midday, wear a hat and sunscreen"
+	case index < 11:
+		return "Very High", "avoid the midday sun, shade, hat and sunscreen are a must"
+	default:
+		return "Extreme", "avoid the midday sun, shade, hat and sunscreen are a must"
+	}
+}
@@ -0,0 +1,35 @@
+package weather
+
+import "testing"
+
+func TestUVRisk(t *testing.T) {
+	const (
+		none     = "no protection needed"
+		midday   = "seek shade at midday, wear a hat and sunscreen"
+		avoidSun = "avoid the midday sun, shade, hat and sunscreen are a must"
+	)
+	tests := []struct {
+		index      float64
+		category   string
+		protection string
+	}{
+		{0, "Low", none},
+		{2, "Low", none},
+		{2.9, "Low", none},
+		{3, "Moderate", midday},
+		{5, "Moderate", midday},
+		{6, "High", midday},
+		{7, "High", midday},
+		{8, "Very High", avoidSun},
+		{10, "Very High", avoidSun},
+		{10.9, "Very High", avoidSun},
+		{11, "Extreme", avoidSun},
+		{14, "Extreme", avoidSun},
+	}
+	for _, tt := range tests {
+		category, protection := UVRisk(tt.index)
+		if category != tt.category || protection != tt.protection {
+			t.Errorf("UVRisk(%g) = %q, %q, want %q, %q", tt.index, category, protection, tt.category, tt.protection)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package weather
+
+import "fmt"
+
+// WeatherCode is a WMO weather interpretation code as returned by Open-Meteo
+type WeatherCode int
+
+var weatherCodeDescriptions = map[WeatherCode]string{
+	0:  "Clear sky",
+	1:  "Mainly clear",
+	2:  "Partly cloudy",
+	3:  "Overcast",
+	45: "Fog",
+	48: "Depositing rime fog",
+	51: "Light drizzle",
+	53: "Moderate drizzle",
+	55: "Dense drizzle",
+	56: "Light freezing drizzle",
+	57: "Dense freezing drizzle",
+	61: "Slight rain",
+	63: "Moderate rain",
+	65: "Heavy rain",
+	66: "Light freezing rain",
+	67: "Heavy freezing rain",
+	71: "Slight snow fall",
+	73: "Moderate snow fall",
+	75: "Heavy snow fall",
+	77: "Snow grains",
+	80: "Slight rain showers",
+	81: "Moderate rain showers",
+	82: "Violent rain showers",
+	85: "Slight snow showers",
+	86: "Heavy snow showers",
+	95: "Thunderstorm",
+	96: "Thunderstorm with slight hail",
+	99: "Thunderstorm with heavy hail",
+}
+
+// String returns a readable description such as "Partly cloudy"
+func (c WeatherCode) String() string {
+	if description, ok := weatherCodeDescriptions[c]; ok {
+		return description
+	}
+	return fmt.Sprintf("Unknown (%d)", int(c))
+}
+
+// Emoji returns an icon for the code
+func (c WeatherCode) Emoji() string {
+	switch {
+	case c == 0:
+		return "☀️"
+	case c == 1:
+		return "🌤️"
+	case c == 2:
+		return "⛅"
+	case c == 3:
+		return "☁️"
+	case c == 45 || c == 48:
+		return "🌫️"
+	case c >= 51 && c <= 57:
+		return "🌦️"
+	case c >= 61 && c <= 67:
+		return "🌧️"
+	case (c >= 71 && c <= 77) || c == 85 || c == 86:
+		return "🌨️"
+	case c >= 80 && c <= 82:
+		return "🌦️"
+	case c >= 95 && c <= 99:
+		return "⛈️"
+	default:
+		return "❔"
+	}
+}
+
+// Category groups the code into one of "clear", "cloudy", "fog", "drizzle",
+// "rain", "snow" or "storm", or "unknown", e.g. for styling
+func (c WeatherCode) Category() string {
+	switch {
+	case c == 0 || c == 1:
+		return "clear"
+	case c == 2 || c == 3:
+		return "cloudy"
+	case c == 45 || c == 48:
+		return "fog"
+	case c >= 51 && c <= 57:
+		return "drizzle"
+	case (c >= 61 && c <= 67) || (c >= 80 && c <= 82):
+		return "rain"
+	case (c >= 71 && c <= 77) || c == 85 || c == 86:
+		return "snow"
+	case c >= 95 && c <= 99:
+		return "storm"
+	default:
+		return "unknown"
+	}
+}
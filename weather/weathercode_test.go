@@ -0,0 +1,75 @@
+package weather
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWeatherCode(t *testing.T) {
+	tests := []struct {
+		code        WeatherCode
+		description string
+		category    string
+	}{
+		{0, "Clear sky", "clear"},
+		{1, "Mainly clear", "clear"},
+		{2, "Partly cloudy", "cloudy"},
+		{3, "Overcast", "cloudy"},
+		{45, "Fog", "fog"},
+		{48, "Depositing rime fog", "fog"},
+		{51, "Light drizzle", "drizzle"},
+		{53, "Moderate drizzle", "drizzle"},
+		{55, "Dense drizzle", "drizzle"},
+		{56, "Light freezing drizzle", "drizzle"},
+		{57, "Dense freezing drizzle", "drizzle"},
+		{61, "Slight rain", "rain"},
+		{63, "Moderate rain", "rain"},
+		{65, "Heavy rain", "rain"},
+		{66, "Light freezing rain", "rain"},
+		{67, "Heavy freezing rain", "rain"},
+		{71, "Slight snow fall", "snow"},
+		{73, "Moderate snow fall", "snow"},
+		{75, "Heavy snow fall", "snow"},
+		{77, "Snow grains", "snow"},
+		{80, "Slight rain showers", "rain"},
+		{81, "Moderate rain showers", "rain"},
+		{82, "Violent rain showers", "rain"},
+		{85, "Slight snow showers", "snow"},
+		{86, "Heavy snow showers", "snow"},
+		{95, "Thunderstorm", "storm"},
+		{96, "Thunderstorm with slight hail", "storm"},
+		{99, "Thunderstorm with heavy hail", "storm"},
+	}
+	if len(tests) != len(weatherCodeDescriptions) {
+		t.Errorf("testing %d codes, the table has %d", len(tests), len(weatherCodeDescriptions))
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(int(tt.code)), func(t *testing.T) {
+			if got := tt.code.String(); got != tt.description {
+				t.Errorf("String() = %q, want %q", got, tt.description)
+			}
+			if got := tt.code.Category(); got != tt.category {
+				t.Errorf("Category() = %q, want %q", got, tt.category)
+			}
+			if got := tt.code.Emoji(); got == "❔" {
+				t.Errorf("Emoji() = %q, want an icon for %s", got, tt.description)
+			}
+		})
+	}
+}
+
+func TestWeatherCodeUnknown(t *testing.T) {
+	for _, code := range []WeatherCode{-1, 4, 50, 58, 100} {
+		t.Run(fmt.Sprint(int(code)), func(t *testing.T) {
+			if got, want := code.String(), fmt.Sprintf("Unknown (%d)", int(code)); got != want {
+				t.Errorf("String() = %q, want %q", got, want)
+			}
+			if got := code.Category(); got != "unknown" {
+				t.Errorf("Category() = %q, want unknown", got)
+			}
+			if got := code.Emoji(); got != "❔" {
+				t.Errorf("Emoji() = %q, want ❔", got)
+			}
+		})
+	}
+}